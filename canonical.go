@@ -0,0 +1,247 @@
+package jsonwalk
+
+import (
+	"fmt"
+	"hash"
+	"math"
+	"sort"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Canonicalize returns the JSON Canonicalization Scheme (RFC 8785) representation of the tree at root:
+// no whitespace, Map keys sorted by their UTF-16 code units, numbers formatted the way ECMAScript does
+// and strings with only the characters that must be escaped being escaped.
+//
+// Equal trees always produce the same bytes, which makes the result suitable for hashing and signing.
+//
+// It panics for the values that can't be represented in JSON, such as NaN or infinite numbers,
+// as well as for the types Walk doesn't know about.
+func Canonicalize(root *interface{}) []byte {
+	return appendCanonical(nil, *root)
+}
+
+// Hash writes the canonical representation of the tree at root, as returned by Canonicalize, to h
+// and returns the resulting sum:
+//
+//	sum := jsonwalk.Hash(&f, sha256.New())
+func Hash(root *interface{}, h hash.Hash) []byte {
+	_, _ = h.Write(Canonicalize(root))
+	return h.Sum(nil)
+}
+
+// SubtreeHash returns a WalkCallback that calls c for every discovered node with a hash of its canonical representation,
+// as returned by Hash for a tree which root is that node. A new hash.Hash is taken from newHash for every node.
+//
+// Identical subtrees produce identical sums, wherever they are found:
+//
+//	seen := map[string]string{}
+//	jsonwalk.Walk(&f, jsonwalk.SubtreeHash(sha256.New, func(path jsonwalk.WalkPath, key interface{}, value interface{}, nodeValueType jsonwalk.NodeValueType, sum []byte) {
+//		if nodeValueType == jsonwalk.Map {
+//			if p, ok := seen[string(sum)]; ok {
+//				fmt.Printf("%v is the same as %v\n", path.Path(), p)
+//			}
+//			seen[string(sum)] = path.Path()
+//		}
+//	}))
+//
+// c is called for the leaves as they are discovered and for the containers when the walk leaves them,
+// after all their children. The canonical representation of a container is built from the ones of its children,
+// so that every node is serialized once.
+func SubtreeHash(newHash func() hash.Hash, c func(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType, sum []byte)) WalkCallback {
+	return &subtreeHash{newHash: newHash, c: c}
+}
+
+// subtreeHash implements WalkLeaveCallback for SubtreeHash.
+type subtreeHash struct {
+	newHash func() hash.Hash
+	c       func(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType, sum []byte)
+	open    []*hashFrame // containers being walked
+}
+
+// hashFrame collects the canonical representations of the children of a container.
+type hashFrame struct {
+	keys     []string
+	children map[string][]byte // for a Map
+	elements [][]byte          // for an Array
+}
+
+func (s *subtreeHash) C(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType) {
+	switch nodeValueType {
+	case Array:
+		s.open = append(s.open, &hashFrame{})
+	case Map:
+		s.open = append(s.open, &hashFrame{children: map[string][]byte{}})
+	default:
+		s.done(path, key, value, nodeValueType, appendCanonical(nil, value))
+	}
+}
+
+func (s *subtreeHash) L(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType) {
+	f := s.open[len(s.open)-1]
+	s.open = s.open[:len(s.open)-1]
+	var b []byte
+	if nodeValueType == Array {
+		b = append(b, '[')
+		for i, el := range f.elements {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = append(b, el...)
+		}
+		b = append(b, ']')
+	} else {
+		sortUTF16(f.keys)
+		b = append(b, '{')
+		for i, k := range f.keys {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = appendCanonicalString(b, k)
+			b = append(b, ':')
+			b = append(b, f.children[k]...)
+		}
+		b = append(b, '}')
+	}
+	s.done(path, key, value, nodeValueType, b)
+}
+
+// done hashes the canonical representation b of a node and hands it to the parent.
+func (s *subtreeHash) done(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType, b []byte) {
+	h := s.newHash()
+	_, _ = h.Write(b)
+	s.c(path, key, value, nodeValueType, h.Sum(nil))
+	if len(s.open) == 0 {
+		return
+	}
+	f := s.open[len(s.open)-1]
+	if f.children == nil {
+		f.elements = append(f.elements, b)
+		return
+	}
+	k := key.(string)
+	f.keys = append(f.keys, k)
+	f.children[k] = b
+}
+
+func appendCanonical(b []byte, v interface{}) []byte {
+	switch vt := v.(type) {
+	case nil:
+		return append(b, "null"...)
+	case bool:
+		return strconv.AppendBool(b, vt)
+	case string:
+		return appendCanonicalString(b, vt)
+	case float64:
+		return appendCanonicalNumber(b, vt)
+	case []interface{}:
+		b = append(b, '[')
+		for i, el := range vt {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = appendCanonical(b, el)
+		}
+		return append(b, ']')
+	case map[string]interface{}:
+		keys := make([]string, 0, len(vt))
+		for k := range vt {
+			keys = append(keys, k)
+		}
+		sortUTF16(keys)
+		b = append(b, '{')
+		for i, k := range keys {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = appendCanonicalString(b, k)
+			b = append(b, ':')
+			b = appendCanonical(b, vt[k])
+		}
+		return append(b, '}')
+	}
+	t(v) // panics for unknown types
+	return b
+}
+
+// appendCanonicalNumber formats f the way ECMAScript Number.prototype.toString does.
+func appendCanonicalNumber(b []byte, f float64) []byte {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		panic(fmt.Sprintf("unsupported number %v", f))
+	}
+	if f == 0 {
+		return append(b, '0') // Including the negative zero
+	}
+	format := byte('f')
+	if abs := math.Abs(f); abs < 1e-6 || abs >= 1e21 {
+		format = 'e'
+	}
+	b = strconv.AppendFloat(b, f, format, -1, 64)
+	if format == 'e' {
+		// ECMAScript doesn't pad the exponent: 1e-7 rather than 1e-07
+		n := len(b)
+		if n >= 4 && b[n-4] == 'e' && b[n-2] == '0' {
+			b[n-2] = b[n-1]
+			b = b[:n-1]
+		}
+	}
+	return b
+}
+
+func appendCanonicalString(b []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+	b = append(b, '"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			switch c {
+			case '"', '\\':
+				b = append(b, '\\', c)
+			case '\b':
+				b = append(b, '\\', 'b')
+			case '\f':
+				b = append(b, '\\', 'f')
+			case '\n':
+				b = append(b, '\\', 'n')
+			case '\r':
+				b = append(b, '\\', 'r')
+			case '\t':
+				b = append(b, '\\', 't')
+			default:
+				if c < 0x20 {
+					b = append(b, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf])
+				} else {
+					b = append(b, c)
+				}
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			b = append(b, "\ufffd"...)
+		} else {
+			b = append(b, s[i:i+size]...)
+		}
+		i += size
+	}
+	return append(b, '"')
+}
+
+// sortUTF16 sorts keys by their UTF-16 code units as required by RFC 8785.
+func sortUTF16(keys []string) {
+	units := make(map[string][]uint16, len(keys))
+	for _, k := range keys {
+		units[k] = utf16.Encode([]rune(k))
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := units[keys[i]], units[keys[j]]
+		for n := 0; n < len(a) && n < len(b); n++ {
+			if a[n] != b[n] {
+				return a[n] < b[n]
+			}
+		}
+		return len(a) < len(b)
+	})
+}
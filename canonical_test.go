@@ -0,0 +1,152 @@
+package jsonwalk_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/zzwx/jsonwalk"
+)
+
+func ExampleCanonicalize() {
+	var f interface{}
+	err := json.Unmarshal([]byte(`{
+		"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+		"string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+		"literals": [null, true, false]
+	}`), &f)
+	if err != nil {
+		return
+	}
+	fmt.Println(string(jsonwalk.Canonicalize(&f)))
+	// Output:
+	// {"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}
+}
+
+func ExampleSubtreeHash() {
+	var f interface{}
+	err := json.Unmarshal([]byte(`[{"a": 1, "b": [true]}, {"c": null}, {"b": [true], "a": 1.0}]`), &f)
+	if err != nil {
+		return
+	}
+	seen := map[string]string{}
+	jsonwalk.Walk(&f, jsonwalk.SubtreeHash(sha256.New, func(path jsonwalk.WalkPath, key interface{}, value interface{}, nodeValueType jsonwalk.NodeValueType, sum []byte) {
+		if nodeValueType == jsonwalk.Map {
+			if p, ok := seen[string(sum)]; ok {
+				fmt.Printf("%v is the same as %v\n", path.Path(), p)
+			}
+			seen[string(sum)] = path.Path()
+		}
+	}))
+	// Output:
+	// [2] is the same as [0]
+}
+
+func TestCanonicalizeNumbers(t *testing.T) {
+	// Examples from RFC 8785, Appendix B.
+	for bits, expected := range map[uint64]string{
+		0x0000000000000000: "0",
+		0x8000000000000000: "0",
+		0x0000000000000001: "5e-324",
+		0x8000000000000001: "-5e-324",
+		0x7fefffffffffffff: "1.7976931348623157e+308",
+		0xffefffffffffffff: "-1.7976931348623157e+308",
+		0x4340000000000000: "9007199254740992",
+		0xc340000000000000: "-9007199254740992",
+		0x4430000000000000: "295147905179352830000",
+		0x44b52d02c7e14af5: "9.999999999999997e+22",
+		0x44b52d02c7e14af6: "1e+23",
+		0x44b52d02c7e14af7: "1.0000000000000001e+23",
+		0x444b1ae4d6e2ef4e: "999999999999999700000",
+		0x444b1ae4d6e2ef4f: "999999999999999900000",
+		0x444b1ae4d6e2ef50: "1e+21",
+		0x3eb0c6f7a0b5ed8c: "9.999999999999997e-7",
+		0x3eb0c6f7a0b5ed8d: "0.000001",
+		0x41b3de4355555553: "333333333.3333332",
+		0x41b3de4355555554: "333333333.33333325",
+		0x41b3de4355555555: "333333333.3333333",
+		0x41b3de4355555556: "333333333.3333334",
+		0x41b3de4355555557: "333333333.33333343",
+		0xbecbf647612f3696: "-0.0000033333333333333333",
+		0x43143ff3c1cb0959: "1424953923781206.2",
+	} {
+		var f interface{} = math.Float64frombits(bits)
+		if got := string(jsonwalk.Canonicalize(&f)); got != expected {
+			t.Errorf("%016x: expected %v, got %v", bits, expected, got)
+		}
+	}
+}
+
+func TestCanonicalizeKeys(t *testing.T) {
+	var f interface{}
+	err := json.Unmarshal([]byte(`{"€": "Euro Sign", "\r": "Carriage Return", "דּ": "Hebrew Letter Dalet With Dagesh",
+		"1": "One", "😀": "Emoji: Grinning Face", "\u0080": "Control", "ö": "Latin Small Letter O With Diaeresis"}`), &f)
+	if err != nil {
+		t.Errorf("error umarshalling json: %v", err)
+		return
+	}
+	expected := "Carriage Return|One|Control|Latin Small Letter O With Diaeresis|Euro Sign|Emoji: Grinning Face|Hebrew Letter Dalet With Dagesh"
+	var values []string
+	dec := json.NewDecoder(bytes.NewReader(jsonwalk.Canonicalize(&f)))
+	for i := 0; ; i++ {
+		tok, err := dec.Token()
+		if err != nil {
+			break
+		}
+		if s, ok := tok.(string); ok && i%2 == 0 {
+			values = append(values, s)
+		}
+	}
+	if got := strings.Join(values, "|"); got != expected {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	var g interface{}
+	_ = json.Unmarshal([]byte(`{"ö": "Latin Small Letter O With Diaeresis", "\u0080": "Control", "😀": "Emoji: Grinning Face",
+		"1": "One", "דּ": "Hebrew Letter Dalet With Dagesh", "\r": "Carriage Return", "€": "Euro Sign"}`), &g)
+	if !bytes.Equal(jsonwalk.Hash(&f, sha256.New()), jsonwalk.Hash(&g, sha256.New())) {
+		t.Errorf("hashes of equal trees are expected to be equal")
+	}
+}
+
+func TestSubtreeHash(t *testing.T) {
+	var f interface{}
+	err := json.Unmarshal([]byte(`{"a": [1, {"é": "x", "e": null, "😀": [true]}], "b": {}, "c": 1e30}`), &f)
+	if err != nil {
+		t.Errorf("error umarshalling json: %v", err)
+		return
+	}
+	nodes := 0
+	jsonwalk.Walk(&f, jsonwalk.SubtreeHash(sha256.New, func(path jsonwalk.WalkPath, key interface{}, value interface{}, nodeValueType jsonwalk.NodeValueType, sum []byte) {
+		nodes++
+		if expected := jsonwalk.Hash(&value, sha256.New()); !bytes.Equal(sum, expected) {
+			t.Errorf("%v: expected %x, got %x", path.Path(), expected, sum)
+		}
+	}))
+	if nodes != 10 {
+		t.Errorf("expected 10 nodes, got %v", nodes)
+	}
+
+	// Deep nesting is serialized once rather than once per level.
+	const depth = 10000
+	var deep interface{} = strings.Repeat("[", depth) + "1" + strings.Repeat("]", depth)
+	if err := json.Unmarshal([]byte(deep.(string)), &deep); err != nil {
+		t.Errorf("error umarshalling json: %v", err)
+		return
+	}
+	var rootSum []byte
+	levels := 0
+	jsonwalk.Walk(&deep, jsonwalk.SubtreeHash(sha256.New, func(path jsonwalk.WalkPath, key interface{}, value interface{}, nodeValueType jsonwalk.NodeValueType, sum []byte) {
+		levels++
+		if path.Level() == 0 {
+			rootSum = sum
+		}
+	}))
+	if levels != depth+1 || !bytes.Equal(rootSum, jsonwalk.Hash(&deep, sha256.New())) {
+		t.Errorf("expected %v nodes and the root hash %x, got %v and %x", depth+1, jsonwalk.Hash(&deep, sha256.New()), levels, rootSum)
+	}
+}
@@ -0,0 +1,366 @@
+package jsonwalk
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// maxBinaryDepth limits the nesting of the CBOR and MessagePack data, which is decoded recursively.
+const maxBinaryDepth = 1000
+
+// binaryReader reads the encoded data, keeping the offset for the error messages.
+type binaryReader struct {
+	r      *bufio.Reader
+	format string
+	off    int64
+	depth  int
+}
+
+func newBinaryReader(r io.Reader, format string) *binaryReader {
+	return &binaryReader{r: bufio.NewReader(r), format: format}
+}
+
+// more reports whether there is anything left to decode.
+func (br *binaryReader) more() (bool, error) {
+	_, err := br.r.Peek(1)
+	if err == io.EOF {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (br *binaryReader) byte() (byte, error) {
+	c, err := br.r.ReadByte()
+	if err != nil {
+		return 0, br.eof(err)
+	}
+	br.off++
+	return c, nil
+}
+
+// bytes reads n bytes, growing the buffer as they arrive, so that a bogus length fails on the end of data
+// rather than on allocation.
+func (br *binaryReader) bytes(n uint64) ([]byte, error) {
+	const chunk = 64 << 10
+	var b []byte
+	for n > 0 {
+		c := uint64(chunk)
+		if n < c {
+			c = n
+		}
+		start := len(b)
+		b = append(b, make([]byte, c)...)
+		if _, err := io.ReadFull(br.r, b[start:]); err != nil {
+			return nil, br.eof(err)
+		}
+		br.off += int64(c)
+		n -= c
+	}
+	return b, nil
+}
+
+func (br *binaryReader) uint(size int) (uint64, error) {
+	b, err := br.bytes(uint64(size))
+	if err != nil {
+		return 0, err
+	}
+	var u uint64
+	for _, c := range b {
+		u = u<<8 | uint64(c)
+	}
+	return u, nil
+}
+
+func (br *binaryReader) eof(err error) error {
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return br.errorf("%w", err)
+}
+
+func (br *binaryReader) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("jsonwalk: %v: offset %d: %w", br.format, br.off, fmt.Errorf(format, args...))
+}
+
+// enter and leave guard the nesting depth.
+func (br *binaryReader) enter() error {
+	br.depth++
+	if br.depth > maxBinaryDepth {
+		return br.errorf("nesting deeper than %d", maxBinaryDepth)
+	}
+	return nil
+}
+
+func (br *binaryReader) leave() {
+	br.depth--
+}
+
+// binaryFloat returns f, or its strconv.FormatFloat form for NaN and infinities, which can't be written as JSON
+// the way DecodeYAML keeps .nan and .inf.
+func binaryFloat(f float64) interface{} {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	return f
+}
+
+// put adds the member with the key k, formatted as a string, to m. Strings are kept as is, anything else
+// is formatted as compact JSON, such as "1", "true" or "[1,2]". A key that formats the same as
+// a key already in m is an error.
+func (br *binaryReader) put(m map[string]interface{}, k interface{}, v interface{}) error {
+	key, ok := k.(string)
+	if !ok {
+		key = compactJSON(k)
+	}
+	if _, ok := m[key]; ok {
+		return br.errorf("duplicate map key %q", key)
+	}
+	m[key] = v
+	return nil
+}
+
+// errBreak is returned by cborDecoder.value for the "break" stop code of an indefinite-length item.
+var errBreak = errors.New("unexpected break")
+
+// DecodeCBOR decodes all the CBOR (RFC 8949) data items read from r, one after another,
+// into the types json.Unmarshal produces, so that they can be walked as any other document:
+//
+//   - integers, including bignums, become float64, the way encoding/json decodes them,
+//     so integers beyond ±2^53, such as nanosecond timestamps, lose their lowest digits;
+//   - floats become float64, except for NaN and infinities, which can't be written as JSON
+//     and become the strings "NaN", "+Inf" and "-Inf";
+//   - byte strings become base64 strings (standard encoding with padding);
+//   - map keys that aren't text strings are formatted as strings: 1 as "1", true as "true",
+//     and collections as compact JSON. Keys that end up the same, such as 1 and "1", are errors;
+//   - undefined becomes nil, other simple values are errors;
+//   - tags are dropped, leaving the tagged items as they are, so a tag 0 date-time is a string
+//     and a tag 1 epoch time is a number.
+func DecodeCBOR(r io.Reader) ([]interface{}, error) {
+	d := cborDecoder{newBinaryReader(r, "cbor")}
+	var items []interface{}
+	for {
+		more, err := d.more()
+		if err != nil {
+			return nil, d.eof(err)
+		}
+		if !more {
+			return items, nil
+		}
+		v, err := d.item()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, v)
+	}
+}
+
+// WalkCBOR decodes the CBOR data items read from r with DecodeCBOR and walks them one after another with Walk.
+func WalkCBOR(r io.Reader, callback WalkCallback) error {
+	items, err := DecodeCBOR(r)
+	if err != nil {
+		return err
+	}
+	for i := range items {
+		Walk(&items[i], callback)
+	}
+	return nil
+}
+
+type cborDecoder struct {
+	*binaryReader
+}
+
+// head reads the initial byte of a data item with its argument. indefinite is true for the additional information 31.
+func (d cborDecoder) head() (major byte, info byte, arg uint64, indefinite bool, err error) {
+	c, err := d.byte()
+	if err != nil {
+		return 0, 0, 0, false, err
+	}
+	major, info = c>>5, c&0x1f
+	switch {
+	case info < 24:
+		return major, info, uint64(info), false, nil
+	case info <= 27:
+		arg, err = d.uint(1 << (info - 24))
+		return major, info, arg, false, err
+	case info == 31 && major >= 2 && major != 6:
+		return major, info, 0, true, nil
+	}
+	return 0, 0, 0, false, d.errorf("malformed initial byte 0x%02x", c)
+}
+
+// item decodes a data item where the "break" stop code is not allowed.
+func (d cborDecoder) item() (interface{}, error) {
+	v, err := d.value()
+	if err == errBreak {
+		return nil, d.errorf("%w", err)
+	}
+	return v, err
+}
+
+// value decodes a data item, returning errBreak for the "break" stop code.
+func (d cborDecoder) value() (interface{}, error) {
+	major, info, arg, indefinite, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+	switch major {
+	case 0:
+		return float64(arg), nil
+	case 1:
+		return -1 - float64(arg), nil
+	case 2, 3:
+		b, err := d.chunks(major, arg, indefinite)
+		if err != nil {
+			return nil, err
+		}
+		if major == 2 {
+			return base64.StdEncoding.EncodeToString(b), nil
+		}
+		return string(b), nil
+	case 4:
+		if err := d.enter(); err != nil {
+			return nil, err
+		}
+		defer d.leave()
+		a := []interface{}{}
+		for i := uint64(0); indefinite || i < arg; i++ {
+			v, err := d.value()
+			if err == errBreak && indefinite {
+				break
+			} else if err == errBreak {
+				return nil, d.errorf("%w", err)
+			}
+			if err != nil {
+				return nil, err
+			}
+			a = append(a, v)
+		}
+		return a, nil
+	case 5:
+		if err := d.enter(); err != nil {
+			return nil, err
+		}
+		defer d.leave()
+		m := map[string]interface{}{}
+		for i := uint64(0); indefinite || i < arg; i++ {
+			k, err := d.value()
+			if err == errBreak && indefinite {
+				break
+			} else if err == errBreak {
+				return nil, d.errorf("%w", err)
+			}
+			if err != nil {
+				return nil, err
+			}
+			v, err := d.item()
+			if err != nil {
+				return nil, err
+			}
+			if err := d.put(m, k, v); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case 6:
+		if err := d.enter(); err != nil {
+			return nil, err
+		}
+		defer d.leave()
+		if arg == 2 || arg == 3 {
+			return d.bignum(arg == 3)
+		}
+		return d.item()
+	}
+	switch {
+	case indefinite:
+		return nil, errBreak
+	case info == 25:
+		return binaryFloat(halfFloat(uint16(arg))), nil
+	case info == 26:
+		return binaryFloat(float64(math.Float32frombits(uint32(arg)))), nil
+	case info == 27:
+		return binaryFloat(math.Float64frombits(arg)), nil
+	}
+	switch arg {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22, 23:
+		return nil, nil
+	}
+	return nil, d.errorf("unsupported simple value %d", arg)
+}
+
+// chunks reads the content of a byte or a text string, joining the chunks of an indefinite-length one.
+func (d cborDecoder) chunks(major byte, n uint64, indefinite bool) ([]byte, error) {
+	if !indefinite {
+		return d.bytes(n)
+	}
+	var b []byte
+	for {
+		m, _, n, indefinite, err := d.head()
+		if err != nil {
+			return nil, err
+		}
+		if m == 7 && indefinite {
+			return b, nil
+		}
+		if m != major || indefinite {
+			return nil, d.errorf("invalid chunk of an indefinite-length string")
+		}
+		c, err := d.bytes(n)
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, c...)
+	}
+}
+
+// bignum decodes the byte string of a tag 2 or 3 bignum.
+func (d cborDecoder) bignum(negative bool) (interface{}, error) {
+	major, _, n, indefinite, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+	if major != 2 {
+		return nil, d.errorf("bignum content is not a byte string")
+	}
+	b, err := d.chunks(major, n, indefinite)
+	if err != nil {
+		return nil, err
+	}
+	f, _ := new(big.Float).SetInt(new(big.Int).SetBytes(b)).Float64()
+	if negative {
+		return -1 - f, nil
+	}
+	return f, nil
+}
+
+// halfFloat converts an IEEE 754 half-precision number.
+func halfFloat(h uint16) float64 {
+	exp, mant := int(h>>10&0x1f), float64(h&0x3ff)
+	var f float64
+	switch exp {
+	case 0:
+		f = math.Ldexp(mant, -24)
+	case 31:
+		f = math.Inf(1)
+		if mant != 0 {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(mant+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		return -f
+	}
+	return f
+}
@@ -0,0 +1,78 @@
+package jsonwalk_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/zzwx/jsonwalk"
+)
+
+func ExampleDecodeCBOR() {
+	// {"a": [1, -2, h'0102'], 1: true, "f": 1.5}
+	data, _ := hex.DecodeString("a36161830121420102" + "01f5" + "6166f93e00")
+	items, err := jsonwalk.DecodeCBOR(bytes.NewReader(data))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	jsonwalk.WalkSorted(&items[0], jsonwalk.Callback(func(path jsonwalk.WalkPath, key interface{}, value interface{}, nodeValueType jsonwalk.NodeValueType) {
+		if nodeValueType != jsonwalk.Array && nodeValueType != jsonwalk.Map {
+			fmt.Printf("%v = %#v\n", path.Path(), value)
+		}
+	}))
+	// Output:
+	// 1 = true
+	// a[0] = 1
+	// a[1] = -2
+	// a[2] = "AQI="
+	// f = 1.5
+}
+
+func TestDecodeCBOR(t *testing.T) {
+	for _, tc := range []struct {
+		hex      string
+		expected string
+	}{
+		{"9f01820203ff", `[1,[2,3]]`},
+		{"7f6261626163ff", `"abc"`},
+		{"c24720000000000000", `9007199254740992`},
+		{"3b001fffffffffffff", `-9007199254740992`},
+		{"c11a514b67b0", `1363896240`},
+		{"bf61610161629f0203ffff", `{"a":1,"b":[2,3]}`},
+		{"a2f4f6a1f50180", `{"false":null,"{\"true\":1}":[]}`},
+		{"f7", `null`},
+		{"fb3ff199999999999a", `1.1`},
+		{"0102", `1 2`},
+		{"1b17978b7a9e300001", `1699980743111868400`}, // 1699980743111868417, rounded like encoding/json does
+		{"3bffffffffffffffff", `-18446744073709552000`},
+		{"c249010000000000000000", `18446744073709552000`},
+		{"83f97e00f97c00fbfff0000000000000", `["NaN","+Inf","-Inf"]`},
+	} {
+		data, _ := hex.DecodeString(tc.hex)
+		items, err := jsonwalk.DecodeCBOR(bytes.NewReader(data))
+		if err != nil {
+			t.Errorf("error decoding %v: %v", tc.hex, err)
+			continue
+		}
+		var got []string
+		for _, v := range items {
+			b, _ := json.Marshal(v)
+			got = append(got, string(b))
+		}
+		if strings.Join(got, " ") != tc.expected {
+			t.Errorf("%v: expected %v, got %v", tc.hex, tc.expected, strings.Join(got, " "))
+		}
+	}
+
+	for _, h := range []string{"ff", "8201ff", "6261", "1c", "a101", "c0", "f0", "5f01ff", strings.Repeat("81", 1001) + "01",
+		"a201f56131f4"} {
+		data, _ := hex.DecodeString(h)
+		if err := jsonwalk.WalkCBOR(bytes.NewReader(data), nil); err == nil {
+			t.Errorf("expected an error for %.20v", h)
+		}
+	}
+}
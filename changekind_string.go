@@ -0,0 +1,27 @@
+// Code generated by "stringer -type ChangeKind"; DO NOT EDIT.
+
+package jsonwalk
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[Added-0]
+	_ = x[Removed-1]
+	_ = x[Changed-2]
+	_ = x[TypeChanged-3]
+}
+
+const _ChangeKind_name = "AddedRemovedChangedTypeChanged"
+
+var _ChangeKind_index = [...]uint8{0, 5, 12, 19, 30}
+
+func (i ChangeKind) String() string {
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_ChangeKind_index)-1 {
+		return "ChangeKind(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _ChangeKind_name[_ChangeKind_index[idx]:_ChangeKind_index[idx+1]]
+}
@@ -0,0 +1,70 @@
+// Command jsonwalk-gotypes prints Go type declarations for the JSON documents given as files or read from the standard input.
+//
+// Usage:
+//
+//	jsonwalk-gotypes [-package name] [-type name] [file ...]
+//
+// All the files are merged into a single structure, so passing several samples of the same API response
+// marks the fields missing from some of them as optional and the fields that were null as pointers.
+// A file can contain a stream of several documents.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/zzwx/jsonwalk"
+)
+
+func main() {
+	pkg := flag.String("package", "", "package name to start the output with")
+	name := flag.String("type", "Root", "name of the root type")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: jsonwalk-gotypes [-package name] [-type name] [file ...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	var docs []*interface{}
+	if flag.NArg() == 0 {
+		docs = decode(os.Stdin, "stdin", docs)
+	}
+	for _, fn := range flag.Args() {
+		f, err := os.Open(fn)
+		if err != nil {
+			fail(err)
+		}
+		docs = decode(f, fn, docs)
+		_ = f.Close()
+	}
+
+	src, err := jsonwalk.GoTypes(jsonwalk.InferSchema(docs...), &jsonwalk.GoOptions{Package: *pkg, Name: *name})
+	if err != nil {
+		fail(err)
+	}
+	_, _ = os.Stdout.Write(src)
+}
+
+// decode appends all the documents found in r to docs.
+func decode(r io.Reader, name string, docs []*interface{}) []*interface{} {
+	dec := json.NewDecoder(r)
+	for {
+		var v interface{}
+		err := dec.Decode(&v)
+		if err == io.EOF {
+			return docs
+		}
+		if err != nil {
+			fail(fmt.Errorf("%v: %w", name, err))
+		}
+		docs = append(docs, &v)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "jsonwalk-gotypes:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,92 @@
+package jsonwalk
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Theme holds the ANSI escape sequences NewColorOutput starts the parts of its output with,
+// such as "\x1b[34m" for blue. Each colored part is followed by a reset. Empty sequences leave the parts uncolored.
+type Theme struct {
+	Key    string // Map keys and array indices
+	String string
+	Number string
+	Bool   string
+	Null   string
+	Path   string // the |path| segments
+	Type   string // the (keyType:valueType) hints
+}
+
+// DefaultTheme is the Theme used by NewColorOutput when none is given.
+var DefaultTheme = Theme{
+	Key:    "\x1b[34m", // blue
+	String: "\x1b[32m", // green
+	Number: "\x1b[36m", // cyan
+	Bool:   "\x1b[33m", // yellow
+	Null:   "\x1b[35m", // magenta
+	Path:   "\x1b[2m",  // faint
+	Type:   "\x1b[2m",  // faint
+}
+
+// ColorMode selects when NewColorOutput colorizes its output.
+type ColorMode int
+
+const (
+	ColorAuto   ColorMode = iota // Only if the writer is a terminal and the NO_COLOR environment variable is not set.
+	ColorAlways                  // Regardless of the writer.
+	ColorNever                   // Never, which gives the same output as NewOutput.
+)
+
+// NewColorOutput returns an Output object initialized with the io.Writer output, same as NewOutput,
+// except that it colorizes the keys, the values by their type, the paths and the type hints with the theme,
+// or with the DefaultTheme if theme is nil:
+//
+//	jsonwalk.Walk(&f, jsonwalk.NewColorOutput(os.Stdout, jsonwalk.ColorAuto, nil))
+//
+// With ColorAuto the output is colorized only if w is an *os.File that is a terminal
+// and the NO_COLOR environment variable (https://no-color.org) is not set or empty.
+func NewColorOutput(w io.Writer, mode ColorMode, theme *Theme) *output {
+	o := NewOutput(w)
+	if mode == ColorAlways || mode == ColorAuto && isTerminal(w) && os.Getenv("NO_COLOR") == "" {
+		if theme == nil {
+			theme = &DefaultTheme
+		}
+		o.theme = theme
+	}
+	return o
+}
+
+// isTerminal reports whether w is a character device, such as a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// paint returns s wrapped in the code and a reset, or s as is for an empty code.
+func (th Theme) paint(code string, s string) string {
+	if code == "" {
+		return s
+	}
+	return code + s + "\x1b[0m"
+}
+
+// paintValue returns the value formatted the way NewOutput does, colored by its type.
+func (th Theme) paintValue(value interface{}, nodeValueType NodeValueType) string {
+	s := fmt.Sprintf("%#v", value)
+	switch nodeValueType {
+	case String:
+		return th.paint(th.String, s)
+	case Float64:
+		return th.paint(th.Number, s)
+	case Bool:
+		return th.paint(th.Bool, s)
+	case Nil:
+		return th.paint(th.Null, s)
+	}
+	return s
+}
@@ -0,0 +1,38 @@
+package jsonwalk_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/zzwx/jsonwalk"
+)
+
+func TestColorOutput(t *testing.T) {
+	var f interface{}
+	err := json.Unmarshal([]byte(`{"a": [1, "x", true, null]}`), &f)
+	if err != nil {
+		t.Errorf("error umarshalling json: %v", err)
+		return
+	}
+	var plain, auto, never, colored bytes.Buffer
+	jsonwalk.WalkSorted(&f, jsonwalk.NewOutput(&plain))
+	jsonwalk.WalkSorted(&f, jsonwalk.NewColorOutput(&auto, jsonwalk.ColorAuto, nil))
+	jsonwalk.WalkSorted(&f, jsonwalk.NewColorOutput(&never, jsonwalk.ColorNever, nil))
+	if auto.String() != plain.String() || never.String() != plain.String() {
+		t.Errorf("expected plain output for a buffer, got:\n%v\n%v", auto.String(), never.String())
+	}
+
+	theme := &jsonwalk.Theme{Key: "<k>", String: "<s>", Number: "<f>", Bool: "<b>", Null: "<n>", Path: "<p>"}
+	jsonwalk.WalkSorted(&f, jsonwalk.NewColorOutput(&colored, jsonwalk.ColorAlways, theme))
+	expected := "(m)\n" +
+		"<k>\"a\"\x1b[0m <p>|a|\x1b[0m (s:a)\n" +
+		"  <k>0\x1b[0m:<f>1\x1b[0m <p>|a[0]|\x1b[0m (0:f)\n" +
+		"  <k>1\x1b[0m:<s>\"x\"\x1b[0m <p>|a[1]|\x1b[0m (1:s)\n" +
+		"  <k>2\x1b[0m:<b>true\x1b[0m <p>|a[2]|\x1b[0m (2:b)\n" +
+		"  <k>3\x1b[0m:<n><nil>\x1b[0m <p>|a[3]|\x1b[0m (3:n)\n"
+	if colored.String() != expected {
+		t.Errorf("expected:\n%q\ngot:\n%q", expected, colored.String())
+	}
+
+}
@@ -0,0 +1,128 @@
+package jsonwalk
+
+// multi broadcasts every discovered node to all of its callbacks.
+type multi []WalkCallback
+
+// Multi returns a WalkCallback that passes every discovered node to each of cbs in the order they are given,
+// which allows running several analyses over the same document in a single Walk.
+// Leaving a container is passed to those of cbs that implement WalkLeaveCallback.
+//
+// Nil callbacks are ignored.
+func Multi(cbs ...WalkCallback) WalkCallback {
+	m := make(multi, 0, len(cbs))
+	for _, cb := range cbs {
+		if cb != nil {
+			m = append(m, cb)
+		}
+	}
+	return m
+}
+
+func (m multi) C(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType) {
+	for _, cb := range m {
+		cb.C(path, key, value, nodeValueType)
+	}
+}
+
+func (m multi) L(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType) {
+	for _, cb := range m {
+		if l, ok := cb.(WalkLeaveCallback); ok {
+			l.L(path, key, value, nodeValueType)
+		}
+	}
+}
+
+// filter delegates to cb only the nodes for which pred returns true.
+type filter struct {
+	pred   func(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType) bool
+	cb     WalkCallback
+	passed []int // levels of the passed containers that haven't been left yet
+}
+
+// Filter returns a WalkCallback that calls cb only for the nodes for which pred returns true.
+//
+// Rejecting a container node doesn't prevent its children from being walked, pred is asked about each of them separately.
+// If cb implements WalkLeaveCallback, leaving a container is passed to it when the container was passed to it,
+// without asking pred again.
+func Filter(pred func(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType) bool, cb WalkCallback) WalkCallback {
+	return &filter{pred: pred, cb: cb}
+}
+
+func (f *filter) C(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType) {
+	if !f.pred(path, key, value, nodeValueType) {
+		return
+	}
+	if nodeValueType == Array || nodeValueType == Map {
+		f.passed = append(f.passed, path.Level())
+	}
+	f.cb.C(path, key, value, nodeValueType)
+}
+
+func (f *filter) L(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType) {
+	if len(f.passed) == 0 || f.passed[len(f.passed)-1] != path.Level() {
+		return
+	}
+	f.passed = f.passed[:len(f.passed)-1]
+	if l, ok := f.cb.(WalkLeaveCallback); ok {
+		l.L(path, key, value, nodeValueType)
+	}
+}
+
+// OnlyTypes returns a WalkCallback that calls cb only for the nodes of the listed types:
+//
+//	jsonwalk.Walk(&f, jsonwalk.OnlyTypes(jsonwalk.Print{}, jsonwalk.String, jsonwalk.Float64))
+func OnlyTypes(cb WalkCallback, types ...NodeValueType) WalkCallback {
+	var allowed [Map + 1]bool
+	for _, tp := range types {
+		if tp >= 0 && tp <= Map {
+			allowed[tp] = true
+		}
+	}
+	return Filter(func(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType) bool {
+		return allowed[nodeValueType]
+	}, cb)
+}
+
+// MaxLevel returns a WalkCallback that calls cb only for the nodes which WalkPath.Level() is not greater than n.
+// MaxLevel(0, cb) only lets the root node through.
+func MaxLevel(n int, cb WalkCallback) WalkCallback {
+	return Filter(func(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType) bool {
+		return path.Level() <= n
+	}, cb)
+}
+
+// limit delegates to cb until n nodes have been passed.
+type limit struct {
+	n      int
+	cb     WalkCallback
+	passed []int // levels of the passed containers that haven't been left yet
+}
+
+// Limit returns a WalkCallback that calls cb only for the first n discovered nodes and ignores the rest.
+//
+// Walk itself still visits every node, Limit only stops delegating. Leaving the containers that were passed to cb
+// is still passed to it if it implements WalkLeaveCallback, so that the output stays well-formed.
+func Limit(n int, cb WalkCallback) WalkCallback {
+	return &limit{n: n, cb: cb}
+}
+
+func (l *limit) C(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType) {
+	if l.n <= 0 {
+		return
+	}
+	l.n--
+	if nodeValueType == Array || nodeValueType == Map {
+		l.passed = append(l.passed, path.Level())
+	}
+	l.cb.C(path, key, value, nodeValueType)
+}
+
+func (l *limit) L(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType) {
+	if len(l.passed) == 0 || l.passed[len(l.passed)-1] != path.Level() {
+		return
+	}
+	l.passed = l.passed[:len(l.passed)-1]
+	if lc, ok := l.cb.(WalkLeaveCallback); ok {
+		lc.L(path, key, value, nodeValueType)
+	}
+}
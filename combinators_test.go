@@ -0,0 +1,109 @@
+package jsonwalk_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/zzwx/jsonwalk"
+)
+
+func ExampleMulti() {
+	var f interface{}
+	err := json.Unmarshal([]byte(`[ "a", 1, [ "b", 2 ], null ]`), &f)
+	if err != nil {
+		return
+	}
+	strings := jsonwalk.OnlyTypes(jsonwalk.Print{}, jsonwalk.String)
+	topLevel := jsonwalk.MaxLevel(0, jsonwalk.Print{})
+	jsonwalk.Walk(&f, jsonwalk.Multi(topLevel, strings))
+	// Output:
+	// (a)
+	// 0:"a" |[0]| (0:s)
+	//   0:"b" |[2][0]| (0:s)
+}
+
+func TestCombinators(t *testing.T) {
+	var f interface{}
+	err := json.Unmarshal([]byte(`{"a": [1, 2, 3, {"b": "c"}], "d": true}`), &f)
+	if err != nil {
+		t.Errorf("error umarshalling json: %v", err)
+		return
+	}
+
+	count := func(n *int) jsonwalk.WalkCallback {
+		return jsonwalk.Callback(func(path jsonwalk.WalkPath, key, value interface{}, vType jsonwalk.NodeValueType) {
+			*n++
+		})
+	}
+
+	var all, numbers, shallow, limited, filtered int
+	jsonwalk.Walk(&f, jsonwalk.Multi(
+		count(&all),
+		jsonwalk.OnlyTypes(count(&numbers), jsonwalk.Float64),
+		jsonwalk.MaxLevel(1, count(&shallow)),
+		jsonwalk.Limit(3, count(&limited)),
+		jsonwalk.Filter(func(path jsonwalk.WalkPath, key, value interface{}, vType jsonwalk.NodeValueType) bool {
+			return key == "b"
+		}, count(&filtered)),
+		nil,
+	))
+
+	for _, c := range []struct {
+		name          string
+		got, expected int
+	}{
+		{"all", all, 8},
+		{"numbers", numbers, 3},
+		{"shallow", shallow, 3},
+		{"limited", limited, 3},
+		{"filtered", filtered, 1},
+	} {
+		if c.got != c.expected {
+			t.Errorf("%v: expected %v nodes, got %v", c.name, c.expected, c.got)
+		}
+	}
+}
+
+func TestLeaveForwarding(t *testing.T) {
+	var f interface{}
+	err := json.Unmarshal([]byte(`{"a": [1, [2]], "b": {}}`), &f)
+	if err != nil {
+		t.Errorf("error umarshalling json: %v", err)
+		return
+	}
+	var events []string
+	record := jsonwalk.LeaveCallback(func(path jsonwalk.WalkPath, key interface{}, value interface{}, nodeValueType jsonwalk.NodeValueType) {
+		events = append(events, "+"+path.Path())
+	}, func(path jsonwalk.WalkPath, key interface{}, value interface{}, nodeValueType jsonwalk.NodeValueType) {
+		events = append(events, "-"+path.Path())
+	})
+	for _, test := range []struct {
+		cb       jsonwalk.WalkCallback
+		expected string
+	}{
+		{record, "[+ +a +a[0] +a[1] +a[1][0] -a[1] -a +b -b -]"},
+		{jsonwalk.Multi(jsonwalk.Callback(func(jsonwalk.WalkPath, interface{}, interface{}, jsonwalk.NodeValueType) {}), nil, record), "[+ +a +a[0] +a[1] +a[1][0] -a[1] -a +b -b -]"},
+		{jsonwalk.OnlyTypes(record, jsonwalk.Array), "[+a +a[1] -a[1] -a]"},
+		{jsonwalk.Limit(4, record), "[+ +a +a[0] +a[1] -a[1] -a -]"},
+	} {
+		events = nil
+		if _, ok := test.cb.(jsonwalk.WalkLeaveCallback); !ok {
+			t.Errorf("%T doesn't implement WalkLeaveCallback", test.cb)
+		}
+		jsonwalk.WalkSorted(&f, test.cb)
+		if got := fmt.Sprint(events); got != test.expected {
+			t.Errorf("%T: expected %v, got %v", test.cb, test.expected, got)
+		}
+	}
+	// The containers are left according to what pred returned on entering them, which is asked once per node.
+	events = nil
+	calls := 0
+	jsonwalk.WalkSorted(&f, jsonwalk.Filter(func(path jsonwalk.WalkPath, key interface{}, value interface{}, nodeValueType jsonwalk.NodeValueType) bool {
+		calls++
+		return calls != 2 // only the first time it's asked about "a"
+	}, record))
+	if got := fmt.Sprint(events); got != "[+ +a[0] +a[1] +a[1][0] -a[1] +b -b -]" || calls != 6 {
+		t.Errorf("expected [+ +a[0] +a[1] +a[1][0] -a[1] +b -b -] after 6 calls, got %v after %v calls", got, calls)
+	}
+}
@@ -0,0 +1,230 @@
+package jsonwalk
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// ChangeKind is the kind of a difference found by Diff.
+type ChangeKind int
+
+const (
+	Added       ChangeKind = iota // The node only exists in the second tree.
+	Removed                       // The node only exists in the first tree.
+	Changed                       // The node is a leaf of the same NodeValueType in both trees, but with a different value.
+	TypeChanged                   // The node has a different NodeValueType in each tree.
+)
+
+// Change is a single difference between two trees.
+type Change struct {
+	// Path of the node. For Removed array elements it's the index in the first tree,
+	// for all the other changes inside arrays it's the index in the second tree.
+	Path WalkPath
+	Kind ChangeKind
+	Old  interface{} // nil for Added
+	New  interface{} // nil for Removed
+}
+
+// ArrayDiffMode selects how array elements of two trees are paired up by Diff.
+type ArrayDiffMode int
+
+const (
+	// ArrayByIndex compares elements with the same index.
+	ArrayByIndex ArrayDiffMode = iota
+	// ArrayLCS finds the longest common subsequence of equal elements and reports the rest as
+	// added or removed. Elements added and removed at the same place are compared with each other instead.
+	ArrayLCS
+	// ArrayByKey compares Map elements that have the same value under the DiffOptions.Key key,
+	// regardless of their position. Other elements are compared by index.
+	ArrayByKey
+)
+
+// DiffOptions controls the comparison performed by DiffWith. A nil *DiffOptions is the same as the zero value.
+type DiffOptions struct {
+	Arrays ArrayDiffMode
+	// Key identifying Map elements of arrays for ArrayByKey, such as "name" or "id".
+	Key string
+}
+
+// Diff returns the differences between trees a and b, comparing arrays by index.
+//
+// Changes are reported for the topmost nodes that differ: an added Map is a single Added change,
+// not a change per each of its leaves. Map keys are compared in the sorted order, so the result is reproducible.
+func Diff(a, b *interface{}) []Change {
+	return DiffWith(a, b, nil)
+}
+
+// DiffWith does the same as Diff with the array comparison selected by opts.
+func DiffWith(a, b *interface{}, opts *DiffOptions) []Change {
+	d := differ{}
+	if opts != nil {
+		d.o = *opts
+	}
+	d.diff(newWalkPath(), *a, *b)
+	return d.changes
+}
+
+type differ struct {
+	o       DiffOptions
+	changes []Change
+}
+
+func (d *differ) add(path WalkPath, kind ChangeKind, old, new interface{}) {
+	d.changes = append(d.changes, Change{Path: path, Kind: kind, Old: old, New: new})
+}
+
+func (d *differ) diff(path walkPath, a, b interface{}) {
+	at, bt := t(a), t(b)
+	if at != bt {
+		d.add(path, TypeChanged, a, b)
+		return
+	}
+	switch at {
+	case Map:
+		am, bm := a.(map[string]interface{}), b.(map[string]interface{})
+		keys := maps.Keys(am)
+		for k := range bm {
+			if _, ok := am[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			av, aok := am[k]
+			bv, bok := bm[k]
+			switch {
+			case !aok:
+				d.add(path.MapEl(k), Added, nil, bv)
+			case !bok:
+				d.add(path.MapEl(k), Removed, av, nil)
+			default:
+				d.diff(path.MapEl(k), av, bv)
+			}
+		}
+	case Array:
+		aa, ba := a.([]interface{}), b.([]interface{})
+		switch d.o.Arrays {
+		case ArrayLCS:
+			d.diffLCS(path, aa, ba)
+		case ArrayByKey:
+			d.diffByKey(path, aa, ba)
+		default:
+			d.diffByIndex(path, aa, ba)
+		}
+	default:
+		if a != b {
+			d.add(path, Changed, a, b)
+		}
+	}
+}
+
+func (d *differ) diffByIndex(path walkPath, a, b []interface{}) {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		switch {
+		case i >= len(a):
+			d.add(path.ArrayEl(i), Added, nil, b[i])
+		case i >= len(b):
+			d.add(path.ArrayEl(i), Removed, a[i], nil)
+		default:
+			d.diff(path.ArrayEl(i), a[i], b[i])
+		}
+	}
+}
+
+func (d *differ) diffLCS(path walkPath, a, b []interface{}) {
+	// Elements removed and added at the same place are compared with each other.
+	alignLCS(a, b, func(ai, aj, bi, bj int) {
+		for ; ai < aj && bi < bj; ai, bi = ai+1, bi+1 {
+			d.diff(path.ArrayEl(bi), a[ai], b[bi])
+		}
+		for ; ai < aj; ai++ {
+			d.add(path.ArrayEl(ai), Removed, a[ai], nil)
+		}
+		for ; bi < bj; bi++ {
+			d.add(path.ArrayEl(bi), Added, nil, b[bi])
+		}
+	}, func(i, j int) {})
+}
+
+func (d *differ) diffByKey(path walkPath, a, b []interface{}) {
+	identity := func(v interface{}) (string, bool) {
+		return keyIdentity(v, d.o.Key)
+	}
+
+	// Indices of the keyed elements of a, in order, for each identity.
+	keyed := map[string][]int{}
+	matched := make([]bool, len(a))
+	for i, v := range a {
+		if id, ok := identity(v); ok {
+			keyed[id] = append(keyed[id], i)
+		}
+	}
+	for j, v := range b {
+		id, ok := identity(v)
+		if !ok {
+			if j < len(a) && !matched[j] {
+				if _, aKeyed := identity(a[j]); !aKeyed {
+					matched[j] = true
+					d.diff(path.ArrayEl(j), a[j], v)
+					continue
+				}
+			}
+			d.add(path.ArrayEl(j), Added, nil, v)
+			continue
+		}
+		if idx := keyed[id]; len(idx) > 0 {
+			keyed[id] = idx[1:]
+			matched[idx[0]] = true
+			d.diff(path.ArrayEl(j), a[idx[0]], v)
+		} else {
+			d.add(path.ArrayEl(j), Added, nil, v)
+		}
+	}
+	for i, v := range a {
+		if !matched[i] {
+			d.add(path.ArrayEl(i), Removed, v, nil)
+		}
+	}
+}
+
+// FprintDiff writes changes to w in a form similar to NewOutput, one change per line.
+// Lines start with a sign of the change kind: "+" for Added, "-" for Removed, "~" for Changed and "!" for TypeChanged.
+// Values are written as compact JSON, followed by the path and the type hints:
+//
+//	~ 56 -> 57 |Actors[0].age| (f)
+//	+ "Connor" |Actors[0].children[2]| (s)
+//	- null |Actors[0].wife| (n)
+//	! null -> "Susan Downey" |Actors[0].wife| (n:s)
+func FprintDiff(w io.Writer, changes []Change) {
+	for _, c := range changes {
+		switch c.Kind {
+		case Added:
+			_, _ = fmt.Fprintf(w, "+ %v |%v| (%v)\n", compactJSON(c.New), c.Path.Path(), typeHint(c.New))
+		case Removed:
+			_, _ = fmt.Fprintf(w, "- %v |%v| (%v)\n", compactJSON(c.Old), c.Path.Path(), typeHint(c.Old))
+		case Changed:
+			_, _ = fmt.Fprintf(w, "~ %v -> %v |%v| (%v)\n", compactJSON(c.Old), compactJSON(c.New), c.Path.Path(), typeHint(c.New))
+		case TypeChanged:
+			_, _ = fmt.Fprintf(w, "! %v -> %v |%v| (%v:%v)\n", compactJSON(c.Old), compactJSON(c.New), c.Path.Path(), typeHint(c.Old), typeHint(c.New))
+		}
+	}
+}
+
+// typeHint returns the one letter type hint used by NewOutput.
+func typeHint(v interface{}) string {
+	return strings.ToLower(t(v).String()[:1])
+}
+
+// compactJSON returns v marshalled into a compact JSON.
+func compactJSON(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%#v", v)
+	}
+	return string(b)
+}
@@ -0,0 +1,94 @@
+package jsonwalk_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/zzwx/jsonwalk"
+)
+
+func ExampleDiff() {
+	var a, b interface{}
+	err := json.Unmarshal([]byte(`{"name": "Tom", "age": 56, "wife": null, "children": ["Suri", "Connor"]}`), &a)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal([]byte(`{"name": "Tom", "age": 57, "wife": "Nicole", "children": ["Suri"], "born": "NY"}`), &b)
+	if err != nil {
+		return
+	}
+	jsonwalk.FprintDiff(os.Stdout, jsonwalk.Diff(&a, &b))
+	// Output:
+	// ~ 56 -> 57 |age| (f)
+	// + "NY" |born| (s)
+	// - "Connor" |children[1]| (s)
+	// ! null -> "Nicole" |wife| (n:s)
+}
+
+func TestDiff(t *testing.T) {
+	var a, b interface{}
+	err := json.Unmarshal([]byte(actorsJSON), &a)
+	if err != nil {
+		t.Errorf("error umarshalling json: %v", err)
+		return
+	}
+	err = json.Unmarshal([]byte(actorsJSON), &b)
+	if err != nil {
+		t.Errorf("error umarshalling json: %v", err)
+		return
+	}
+	if changes := jsonwalk.Diff(&a, &b); len(changes) != 0 {
+		t.Errorf("expected no changes, got %v", changes)
+	}
+
+	// Swap the actors, change an age and insert a child.
+	actors := b.(map[string]interface{})["Actors"].([]interface{})
+	actors[0], actors[1] = actors[1], actors[0]
+	actors[0].(map[string]interface{})["age"] = 54.0
+	tom := actors[1].(map[string]interface{})
+	tom["children"] = append([]interface{}{"Bella"}, tom["children"].([]interface{})...)
+
+	expected := []string{
+		"Changed Actors[0].age 53 54",
+		"Changed Actors[1].children[0] Suri Bella",
+		"Changed Actors[1].children[1] Isabella Jane Suri",
+		"Changed Actors[1].children[2] Connor Isabella Jane",
+		"Added Actors[1].children[3] <nil> Connor",
+	}
+	var got []string
+	for _, ch := range jsonwalk.DiffWith(&a, &b, &jsonwalk.DiffOptions{Arrays: jsonwalk.ArrayByKey, Key: "name"}) {
+		got = append(got, fmt.Sprintf("%v %v %v %v", ch.Kind, ch.Path.Path(), ch.Old, ch.New))
+	}
+	if fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("expected\n%v\ngot\n%v", expected, got)
+	}
+
+	var x, y interface{}
+	err = json.Unmarshal([]byte(`[1, 2, 3, 4, 5]`), &x)
+	if err != nil {
+		t.Errorf("error umarshalling json: %v", err)
+		return
+	}
+	err = json.Unmarshal([]byte(`[0, 1, 2, 9, 4, 5, 6]`), &y)
+	if err != nil {
+		t.Errorf("error umarshalling json: %v", err)
+		return
+	}
+	for _, c := range []struct {
+		opts     *jsonwalk.DiffOptions
+		expected string
+	}{
+		{nil, "[Changed [0] 1 0 Changed [1] 2 1 Changed [2] 3 2 Changed [3] 4 9 Changed [4] 5 4 Added [5] <nil> 5 Added [6] <nil> 6]"},
+		{&jsonwalk.DiffOptions{Arrays: jsonwalk.ArrayLCS}, "[Added [0] <nil> 0 Changed [3] 3 9 Added [6] <nil> 6]"},
+	} {
+		var got []string
+		for _, ch := range jsonwalk.DiffWith(&x, &y, c.opts) {
+			got = append(got, fmt.Sprintf("%v %v %v %v", ch.Kind, ch.Path.Path(), ch.Old, ch.New))
+		}
+		if fmt.Sprint(got) != c.expected {
+			t.Errorf("options %+v: expected %v, got %v", c.opts, c.expected, got)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package jsonwalk
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DOTOptions configures NewDOTWriter. A nil *DOTOptions is the same as the zero value.
+type DOTOptions struct {
+	// Name of the graph, "jsonwalk" if empty.
+	Name string
+	// CollapseIdentical draws a single subtree for the identical elements of an array,
+	// with the edges of all of them pointing to the first one.
+	CollapseIdentical bool
+	// MaxDepth leaves out the nodes which WalkPath.Level() is greater than MaxDepth,
+	// marking the containers at the MaxDepth level with "…". 0 means no limit.
+	MaxDepth int
+}
+
+// dotWriter implements WalkLeaveCallback by writing the walked document as a Graphviz graph.
+type dotWriter struct {
+	w    io.Writer
+	opts DOTOptions
+	ids  []string            // node ids of the open containers, by level
+	seen []map[string]string // node ids of the elements of the open containers by their canonical form
+	next int
+	skip int // level of the collapsed element which children are being skipped, or -1
+	err  error
+}
+
+// NewDOTWriter returns a WalkLeaveCallback that writes the walked document to w as a Graphviz DOT digraph:
+// a box node for every Map or Array, a record node with the value and its type hint for every leaf,
+// and edges from the containers to their children labeled with the keys or indices:
+//
+//	digraph "jsonwalk" {
+//		n0 [shape=box, label="Map (1)"];
+//		n1 [shape=box, label="Array (2)"];
+//		n0 -> n1 [label="Actors"];
+//		...
+//	}
+//
+// Nodes are written as soon as they are discovered, relying on the parent being discovered before its children.
+// The graph is closed when the walk leaves the root, so the callback has to be passed to Walk directly
+// or through the callbacks that forward WalkLeaveCallback.L, such as Multi, Filter or Limit.
+func NewDOTWriter(w io.Writer, opts *DOTOptions) *dotWriter {
+	dw := &dotWriter{w: w, skip: -1}
+	if opts != nil {
+		dw.opts = *opts
+	}
+	if dw.opts.Name == "" {
+		dw.opts.Name = "jsonwalk"
+	}
+	return dw
+}
+
+// Err returns the first error that occurred while writing. Nothing is written after an error.
+func (dw *dotWriter) Err() error {
+	return dw.err
+}
+
+func (dw *dotWriter) C(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType) {
+	level := path.Level()
+	if dw.err != nil || dw.skip >= 0 && level > dw.skip {
+		return
+	}
+	dw.skip = -1
+	if dw.opts.MaxDepth > 0 && level > dw.opts.MaxDepth || level > len(dw.ids) {
+		return
+	}
+	dw.ids, dw.seen = dw.ids[:level], dw.seen[:level]
+	var b strings.Builder
+	if level == 0 {
+		_, _ = fmt.Fprintf(&b, "digraph %v {\n", dotQuote(dw.opts.Name))
+	}
+
+	edge := ""
+	if i, ok := key.(int); ok {
+		edge = fmt.Sprintf("[%d]", i)
+	} else if level > 0 {
+		edge = fmt.Sprint(key)
+	}
+	if level > 0 && dw.opts.CollapseIdentical && dw.seen[level-1] != nil {
+		canonical := string(Canonicalize(&value))
+		if id, ok := dw.seen[level-1][canonical]; ok {
+			_, _ = fmt.Fprintf(&b, "\t%v -> %v [label=%v];\n", dw.ids[level-1], id, dotQuote(edge))
+			dw.skip = level
+			dw.write(b.String())
+			return
+		}
+		dw.seen[level-1][canonical] = dw.id()
+	}
+
+	id := dw.id()
+	dw.next++
+	switch nodeValueType {
+	case Array, Map:
+		n := 0
+		if nodeValueType == Array {
+			n = len(value.([]interface{}))
+		} else {
+			n = len(value.(map[string]interface{}))
+		}
+		label := fmt.Sprintf("%v (%d)", nodeValueType, n)
+		if dw.opts.MaxDepth > 0 && level == dw.opts.MaxDepth && n > 0 {
+			label += " …"
+		}
+		_, _ = fmt.Fprintf(&b, "\t%v [shape=box, label=%v];\n", id, dotQuote(label))
+		dw.ids = append(dw.ids, id)
+		var seen map[string]string
+		if nodeValueType == Array && dw.opts.CollapseIdentical {
+			seen = map[string]string{}
+		}
+		dw.seen = append(dw.seen, seen)
+	default:
+		_, _ = fmt.Fprintf(&b, "\t%v [shape=record, label=\"{%v|%v}\"];\n",
+			id, escapeRecord(compactJSON(value)), strings.ToLower(nodeValueType.String()[:1]))
+	}
+	if level > 0 {
+		_, _ = fmt.Fprintf(&b, "\t%v -> %v [label=%v];\n", dw.ids[level-1], id, dotQuote(edge))
+	} else if nodeValueType != Array && nodeValueType != Map {
+		b.WriteString("}\n") // a root leaf is the whole graph
+	}
+	dw.write(b.String())
+}
+
+func (dw *dotWriter) L(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType) {
+	if dw.err != nil || path.Level() != 0 || len(dw.ids) == 0 {
+		return
+	}
+	dw.ids, dw.seen = dw.ids[:0], dw.seen[:0]
+	dw.write("}\n")
+}
+
+// id returns the id of the next node.
+func (dw *dotWriter) id() string {
+	return fmt.Sprintf("n%d", dw.next)
+}
+
+func (dw *dotWriter) write(s string) {
+	if _, err := io.WriteString(dw.w, s); err != nil {
+		dw.err = err
+	}
+}
+
+// dotQuote returns s as a DOT quoted string.
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// escapeRecord escapes s for a field of a DOT record label.
+func escapeRecord(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "{", `\{`, "}", `\}`, "|", `\|`, "<", `\<`, ">", `\>`, " ", `\ `, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,62 @@
+package jsonwalk_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/zzwx/jsonwalk"
+)
+
+func ExampleNewDOTWriter() {
+	var f interface{}
+	err := json.Unmarshal([]byte(`{"tags": ["a", "a", {"x": 1}, {"x": 1}], "name": "Tom \"T\" {C}"}`), &f)
+	if err != nil {
+		return
+	}
+	jsonwalk.WalkSorted(&f, jsonwalk.NewDOTWriter(os.Stdout, &jsonwalk.DOTOptions{CollapseIdentical: true}))
+	// Output:
+	// digraph "jsonwalk" {
+	// 	n0 [shape=box, label="Map (2)"];
+	// 	n1 [shape=record, label="{\"Tom\ \\\"T\\\"\ \{C\}\"|s}"];
+	// 	n0 -> n1 [label="name"];
+	// 	n2 [shape=box, label="Array (4)"];
+	// 	n0 -> n2 [label="tags"];
+	// 	n3 [shape=record, label="{\"a\"|s}"];
+	// 	n2 -> n3 [label="[0]"];
+	// 	n2 -> n3 [label="[1]"];
+	// 	n4 [shape=box, label="Map (1)"];
+	// 	n2 -> n4 [label="[2]"];
+	// 	n5 [shape=record, label="{1|f}"];
+	// 	n4 -> n5 [label="x"];
+	// 	n2 -> n4 [label="[3]"];
+	// }
+}
+
+func TestDOTWriter(t *testing.T) {
+	var f interface{}
+	err := json.Unmarshal([]byte(`[{"a": [1]}, 2]`), &f)
+	if err != nil {
+		t.Errorf("error umarshalling json: %v", err)
+		return
+	}
+	var b bytes.Buffer
+	dw := jsonwalk.NewDOTWriter(&b, &jsonwalk.DOTOptions{Name: "g", MaxDepth: 1})
+	jsonwalk.Walk(&f, dw)
+	var s interface{} = true
+	jsonwalk.Walk(&s, dw)
+	expected := "digraph \"g\" {\n" +
+		"\tn0 [shape=box, label=\"Array (2)\"];\n" +
+		"\tn1 [shape=box, label=\"Map (1) …\"];\n" +
+		"\tn0 -> n1 [label=\"[0]\"];\n" +
+		"\tn2 [shape=record, label=\"{2|f}\"];\n" +
+		"\tn0 -> n2 [label=\"[1]\"];\n" +
+		"}\n" +
+		"digraph \"g\" {\n" +
+		"\tn3 [shape=record, label=\"{true|b}\"];\n" +
+		"}\n"
+	if b.String() != expected || dw.Err() != nil {
+		t.Errorf("expected:\n%v\ngot:\n%v", expected, b.String())
+	}
+}
@@ -0,0 +1,184 @@
+package jsonwalk
+
+import (
+	"fmt"
+	"math"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// EqualOption relaxes the comparison made by Equal.
+type EqualOption func(*equalOptions)
+
+type equalOptions struct {
+	tolerance         float64
+	ignore            [][]pathSeg
+	unordered         [][]pathSeg
+	allUnordered      bool
+	nullEqualsMissing bool
+}
+
+// FloatTolerance makes numbers equal if they differ by no more than eps.
+func FloatTolerance(eps float64) EqualOption {
+	return func(o *equalOptions) {
+		o.tolerance = math.Abs(eps)
+	}
+}
+
+// IgnorePaths skips the comparison of the nodes which paths match any of the patterns, along with their children.
+// Patterns are paths in the form returned by WalkPath.Path(), where "*" matches any Map key,
+// "[*]" matches any array index and "**" matches any number of path elements, such as "**.id" or "meta.generated".
+//
+// It panics if a pattern can't be parsed.
+func IgnorePaths(patterns ...string) EqualOption {
+	parsed := mustParsePatterns(patterns)
+	return func(o *equalOptions) {
+		o.ignore = append(o.ignore, parsed...)
+	}
+}
+
+// UnorderedArrays compares arrays as multisets, regardless of the order of their elements.
+// Without patterns it applies to all the arrays, otherwise only to the arrays which paths match
+// any of the patterns, written as for IgnorePaths.
+//
+// Elements are matched greedily: each element of the first array is paired with the first equal
+// unpaired element of the second one.
+func UnorderedArrays(patterns ...string) EqualOption {
+	parsed := mustParsePatterns(patterns)
+	return func(o *equalOptions) {
+		if len(parsed) == 0 {
+			o.allUnordered = true
+		}
+		o.unordered = append(o.unordered, parsed...)
+	}
+}
+
+// NullEqualsMissing makes a Map member that is null equal to the same member not being present at all.
+func NullEqualsMissing() EqualOption {
+	return func(o *equalOptions) {
+		o.nullEqualsMissing = true
+	}
+}
+
+func mustParsePatterns(patterns []string) [][]pathSeg {
+	var parsed [][]pathSeg
+	for _, p := range patterns {
+		segs, err := parsePath(p, ".", true)
+		if err != nil {
+			panic(fmt.Sprintf("invalid path pattern %q: %v", p, err))
+		}
+		parsed = append(parsed, segs)
+	}
+	return parsed
+}
+
+// Equal reports whether trees a and b are equal, relaxing the comparison with opts:
+//
+//	eq, at := jsonwalk.Equal(&a, &b, jsonwalk.FloatTolerance(1e-9), jsonwalk.IgnorePaths("**.id"))
+//
+// If they are not equal, it also returns the path of the first mismatching node,
+// discovering Map keys in the sorted order. For arrays of different lengths that's the path of the array itself.
+func Equal(a, b *interface{}, opts ...EqualOption) (bool, WalkPath) {
+	var o equalOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	e := equaler{o: o}
+	if p, ok := e.equal(newWalkPath(), *a, *b); !ok {
+		return false, p
+	}
+	return true, nil
+}
+
+type equaler struct {
+	o equalOptions
+}
+
+func matchAny(patterns [][]pathSeg, segs []pathSeg) bool {
+	for _, p := range patterns {
+		if matchSegs(p, segs) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *equaler) equal(path walkPath, a, b interface{}) (walkPath, bool) {
+	if len(e.o.ignore) > 0 && matchAny(e.o.ignore, path.segments()) {
+		return path, true
+	}
+	at, bt := t(a), t(b)
+	if at != bt {
+		return path, false
+	}
+	switch at {
+	case Float64:
+		if d := math.Abs(a.(float64) - b.(float64)); d != 0 && !(d <= e.o.tolerance) {
+			return path, false
+		}
+		return path, true
+	case Map:
+		am, bm := a.(map[string]interface{}), b.(map[string]interface{})
+		keys := maps.Keys(am)
+		for k := range bm {
+			if _, ok := am[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			av, aok := am[k]
+			bv, bok := bm[k]
+			if !aok || !bok {
+				if e.o.nullEqualsMissing && av == nil && bv == nil {
+					continue
+				}
+				if len(e.o.ignore) > 0 && matchAny(e.o.ignore, path.MapEl(k).segments()) {
+					continue
+				}
+				return path.MapEl(k), false
+			}
+			if p, ok := e.equal(path.MapEl(k), av, bv); !ok {
+				return p, false
+			}
+		}
+		return path, true
+	case Array:
+		aa, ba := a.([]interface{}), b.([]interface{})
+		if len(aa) != len(ba) {
+			return path, false
+		}
+		if e.o.allUnordered || len(e.o.unordered) > 0 && matchAny(e.o.unordered, path.segments()) {
+			return e.equalUnordered(path, aa, ba)
+		}
+		for i := range aa {
+			if p, ok := e.equal(path.ArrayEl(i), aa[i], ba[i]); !ok {
+				return p, false
+			}
+		}
+		return path, true
+	}
+	return path, a == b
+}
+
+func (e *equaler) equalUnordered(path walkPath, a, b []interface{}) (walkPath, bool) {
+	used := make([]bool, len(b))
+	for i, av := range a {
+		found := false
+		for j, bv := range b {
+			if used[j] {
+				continue
+			}
+			if _, ok := e.equal(path.ArrayEl(i), av, bv); ok {
+				used[j] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return path.ArrayEl(i), false
+		}
+	}
+	return path, true
+}
@@ -0,0 +1,60 @@
+package jsonwalk_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/zzwx/jsonwalk"
+)
+
+func ExampleEqual() {
+	var a, b interface{}
+	err := json.Unmarshal([]byte(`{"id": 1, "Actors": [{"name": "Tom Cruise", "weight": 67.5, "children": ["Suri", "Connor"]}]}`), &a)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal([]byte(`{"id": 2, "Actors": [{"name": "Tom Cruise", "weight": 67.50001, "children": ["Connor", "Isabella"]}]}`), &b)
+	if err != nil {
+		return
+	}
+	eq, at := jsonwalk.Equal(&a, &b,
+		jsonwalk.FloatTolerance(0.001),
+		jsonwalk.IgnorePaths("id"),
+		jsonwalk.UnorderedArrays("**.children"),
+	)
+	fmt.Println(eq, at.Path())
+	// Output:
+	// false Actors[0].children[0]
+}
+
+func TestEqual(t *testing.T) {
+	var a, b interface{}
+	err := json.Unmarshal([]byte(`{"a": [1, 2, {"x": null}], "b": 0.1, "c": null, "meta": {"generated": "now"}}`), &a)
+	if err != nil {
+		t.Errorf("error umarshalling json: %v", err)
+		return
+	}
+	_ = json.Unmarshal([]byte(`{"a": [{}, 2, 1], "b": 0.10000001, "meta": {"generated": "later"}}`), &b)
+
+	for _, test := range []struct {
+		opts     []jsonwalk.EqualOption
+		expected string
+	}{
+		{nil, "a[0]"},
+		{[]jsonwalk.EqualOption{jsonwalk.UnorderedArrays()}, "a[2]"},
+		{[]jsonwalk.EqualOption{jsonwalk.UnorderedArrays(), jsonwalk.NullEqualsMissing()}, "b"},
+		{[]jsonwalk.EqualOption{jsonwalk.UnorderedArrays(), jsonwalk.NullEqualsMissing(), jsonwalk.FloatTolerance(1e-6)}, "meta.generated"},
+		{[]jsonwalk.EqualOption{jsonwalk.UnorderedArrays(), jsonwalk.NullEqualsMissing(), jsonwalk.FloatTolerance(1e-6), jsonwalk.IgnorePaths("meta.*")}, ""},
+		{[]jsonwalk.EqualOption{jsonwalk.UnorderedArrays(), jsonwalk.FloatTolerance(1e-6), jsonwalk.IgnorePaths("meta", "c")}, "a[2]"},
+	} {
+		eq, at := jsonwalk.Equal(&a, &b, test.opts...)
+		got := ""
+		if at != nil {
+			got = at.Path()
+		}
+		if eq != (test.expected == "") || got != test.expected {
+			t.Errorf("%d options: expected mismatch at %q, got %v at %q", len(test.opts), test.expected, eq, got)
+		}
+	}
+}
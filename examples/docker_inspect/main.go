@@ -56,42 +56,30 @@ func analyze(src []byte) {
 	}
 	//jsonwalk.Walk(&v, jsonwalk.Print{})
 
-	name := ""
-	hostName := ""
-	status := ""
-	var env []string
-	workingDir := ""
+	var c struct {
+		Name       string   `jsonwalk:"[0].Name,required"`
+		HostName   string   `jsonwalk:"[0].Config.Hostname"`
+		Status     string   `jsonwalk:"[0].State.Status"`
+		Env        []string `jsonwalk:"[0].Config.Env"`
+		WorkingDir string   `jsonwalk:"[0].Config.WorkingDir"`
+		Image      string   `jsonwalk:"[0].Config.Image"`
+		Names      []string `jsonwalk:"[*].Name"`
+	}
+	err = jsonwalk.Extract(&v, &c)
+	if err != nil {
+		log.Fatalln(err)
+	}
 	warning := ""
-	image := ""
-
-	jsonwalk.Walk(&v, jsonwalk.Callback(func(path jsonwalk.WalkPath, key interface{}, value interface{}, tp jsonwalk.NodeValueType) {
-		if path.Path() == "[0].Config.Env" && tp == jsonwalk.Array {
-			for _, v := range value.([]interface{}) {
-				env = append(env, v.(string))
-			}
-		} else if (path.Path() == "[0].Config.Hostname") && tp == jsonwalk.String {
-			hostName = value.(string)
-		} else if (path.Path() == "[0].Name") && tp == jsonwalk.String {
-			name = value.(string)
-		} else if (path.Path() == "[0].State.Status") && tp == jsonwalk.String {
-			status = value.(string)
-		} else if (path.Path() == "[0].Config.WorkingDir") && tp == jsonwalk.String {
-			workingDir = value.(string)
-		} else if (path.Path() == "[0].Config.Image") && tp == jsonwalk.String {
-			image = value.(string)
-		} else if strings.HasPrefix(path.Path(), "[1]") {
-			warning = "--- warning: [1] found in the array"
-		}
-
-	}))
+	if len(c.Names) > 1 {
+		warning = "--- warning: [1] found in the array"
+	}
 
-	fmt.Printf("%v\n", image)
-	fmt.Printf("%v | %v | %v | %v\n", name, hostName, status, workingDir)
-	for _, e := range env {
+	fmt.Printf("%v\n", c.Image)
+	fmt.Printf("%v | %v | %v | %v\n", c.Name, c.HostName, c.Status, c.WorkingDir)
+	for _, e := range c.Env {
 		fmt.Printf("%v\n", e)
 	}
 	if warning != "" {
 		fmt.Printf("%v\n", warning)
 	}
-
 }
@@ -0,0 +1,328 @@
+package jsonwalk
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ErrMissing is wrapped by ExtractError when a field marked as required has no matching node.
+var ErrMissing = errors.New("required value is missing")
+
+// ExtractError describes a failure to fill a single struct field by Extract.
+type ExtractError struct {
+	Field string // Name of the struct field, "."-separated for fields of nested structs.
+	Path  string // Path of the node that failed to convert, or the tag path if nothing matched.
+	Err   error
+}
+
+func (e *ExtractError) Error() string {
+	return fmt.Sprintf("jsonwalk: field %v (%v): %v", e.Field, e.Path, e.Err)
+}
+
+func (e *ExtractError) Unwrap() error {
+	return e.Err
+}
+
+// ExtractErrors is returned by Extract when one or more fields could not be filled.
+type ExtractErrors []*ExtractError
+
+func (e ExtractErrors) Error() string {
+	s := make([]string, len(e))
+	for i, err := range e {
+		s[i] = err.Error()
+	}
+	return strings.Join(s, "\n")
+}
+
+// Extract fills the fields of the struct pointed to by dst with the values found in root
+// at the paths given by the "jsonwalk" field tags.
+//
+// Paths are written the same way WalkPath.Path() returns them, with "[*]" matching any array index
+// and "*" matching any map key. Backslash escapes ".", "[", "]", "*" and "\" that are part of a key.
+// Tagged fields can be marked with a ",required" option to report them if the path is not found.
+//
+//	var c struct {
+//		Name  string   `jsonwalk:"[0].Name,required"`
+//		Env   []string `jsonwalk:"[0].Config.Env"`
+//		Names []string `jsonwalk:"[*].Name"`
+//	}
+//	err := jsonwalk.Extract(&v, &c)
+//
+// Paths with wildcards require a slice field and fill it with all the matches in the order they are discovered
+// by WalkSorted, so that the matches of a map wildcard are ordered by their keys.
+//
+// Values are converted to the field types: numbers to any of the numeric types as long as they fit,
+// strings to numbers and bools by parsing them, numbers and bools to strings,
+// arrays to slices and maps to maps with string keys or, recursively, to structs with their own "jsonwalk" tags
+// relative to the map. Pointer fields are allocated and null values leave the fields untouched.
+//
+// Fields that can't be filled don't stop the extraction, they are all reported at once in ExtractErrors.
+func Extract(root *interface{}, dst interface{}) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("jsonwalk: Extract requires a non-nil pointer to a struct, got %T", dst)
+	}
+	var errs ExtractErrors
+	extractStruct(root, rv.Elem(), "", "", &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// binding is a struct field with its parsed tag.
+type binding struct {
+	field    reflect.Value
+	name     string
+	tagPath  string
+	pattern  []pathSeg
+	required bool
+	many     bool
+	values   []interface{}
+	paths    []string
+}
+
+func extractStruct(root *interface{}, sv reflect.Value, fieldPrefix, pathPrefix string, errs *ExtractErrors) {
+	var bindings []*binding
+	st := sv.Type()
+	for i := 0; i < st.NumField(); i++ {
+		sf := st.Field(i)
+		tag, ok := sf.Tag.Lookup("jsonwalk")
+		if !ok || tag == "-" || !sf.IsExported() {
+			continue
+		}
+		b := &binding{field: sv.Field(i), name: fieldPrefix + sf.Name}
+		b.tagPath, b.required = parseTag(tag)
+		pattern, err := parsePath(b.tagPath, ".", true)
+		if err != nil {
+			*errs = append(*errs, &ExtractError{Field: b.name, Path: b.tagPath, Err: err})
+			continue
+		}
+		b.pattern = pattern
+		b.many = hasWildcards(pattern)
+		if b.many && sf.Type.Kind() != reflect.Slice {
+			*errs = append(*errs, &ExtractError{Field: b.name, Path: b.tagPath, Err: fmt.Errorf("path with wildcards requires a slice, got %v", sf.Type)})
+			continue
+		}
+		bindings = append(bindings, b)
+	}
+	if len(bindings) == 0 {
+		return
+	}
+
+	WalkSorted(root, Callback(func(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType) {
+		segs := pathSegments(path)
+		for _, b := range bindings {
+			if (b.many || len(b.values) == 0) && matchSegs(b.pattern, segs) {
+				b.values = append(b.values, value)
+				b.paths = append(b.paths, joinPath(pathPrefix, path.Path()))
+			}
+		}
+	}))
+
+	for _, b := range bindings {
+		if len(b.values) == 0 {
+			if b.required {
+				*errs = append(*errs, &ExtractError{Field: b.name, Path: joinPath(pathPrefix, b.tagPath), Err: ErrMissing})
+			}
+			continue
+		}
+		if !b.many {
+			assign(b.field, b.values[0], b.name, b.paths[0], errs)
+			continue
+		}
+		s := reflect.MakeSlice(b.field.Type(), len(b.values), len(b.values))
+		ok := true
+		for i, v := range b.values {
+			ok = assign(s.Index(i), v, b.name, b.paths[i], errs) && ok
+		}
+		if ok {
+			b.field.Set(s)
+		}
+	}
+}
+
+// parseTag splits a "jsonwalk" tag into the path and its options.
+func parseTag(tag string) (path string, required bool) {
+	// The path itself can contain escaped commas, so only the last unescaped one separates the options.
+	for {
+		i := strings.LastIndexByte(tag, ',')
+		if i < 0 || (i > 0 && tag[i-1] == '\\') {
+			return tag, required
+		}
+		if strings.TrimSpace(tag[i+1:]) == "required" {
+			required = true
+		}
+		tag = tag[:i]
+	}
+}
+
+// joinPath appends path p to the prefix the same way WalkPath.Path() does.
+func joinPath(prefix, p string) string {
+	if prefix == "" || p == "" {
+		return prefix + p
+	}
+	if strings.HasPrefix(p, "[") {
+		return prefix + p
+	}
+	return prefix + "." + p
+}
+
+// assign converts v to the type of dst and sets it. It reports whether it succeeded, adding an error to errs otherwise.
+func assign(dst reflect.Value, v interface{}, field, path string, errs *ExtractErrors) bool {
+	fail := func(err error) bool {
+		*errs = append(*errs, &ExtractError{Field: field, Path: path, Err: err})
+		return false
+	}
+	if v == nil {
+		return true
+	}
+	mismatch := func() bool {
+		return fail(fmt.Errorf("cannot use %v value as %v", t(v), dst.Type()))
+	}
+
+	switch dst.Kind() {
+	case reflect.Pointer:
+		p := reflect.New(dst.Type().Elem())
+		if !assign(p.Elem(), v, field, path, errs) {
+			return false
+		}
+		dst.Set(p)
+	case reflect.Interface:
+		if !reflect.TypeOf(v).AssignableTo(dst.Type()) {
+			return mismatch()
+		}
+		dst.Set(reflect.ValueOf(v))
+	case reflect.String:
+		switch vt := v.(type) {
+		case string:
+			dst.SetString(vt)
+		case float64:
+			dst.SetString(strconv.FormatFloat(vt, 'f', -1, 64))
+		case bool:
+			dst.SetString(strconv.FormatBool(vt))
+		default:
+			return mismatch()
+		}
+	case reflect.Bool:
+		switch vt := v.(type) {
+		case bool:
+			dst.SetBool(vt)
+		case string:
+			b, err := strconv.ParseBool(vt)
+			if err != nil {
+				return fail(err)
+			}
+			dst.SetBool(b)
+		default:
+			return mismatch()
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		switch vt := v.(type) {
+		case float64:
+			if vt != math.Trunc(vt) || vt < math.MinInt64 || vt >= math.MaxInt64 {
+				return fail(fmt.Errorf("%v is not an integer that fits %v", vt, dst.Type()))
+			}
+			n = int64(vt)
+		case string:
+			var err error
+			n, err = strconv.ParseInt(vt, 10, 64)
+			if err != nil {
+				return fail(err)
+			}
+		default:
+			return mismatch()
+		}
+		if dst.OverflowInt(n) {
+			return fail(fmt.Errorf("%v overflows %v", n, dst.Type()))
+		}
+		dst.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var n uint64
+		switch vt := v.(type) {
+		case float64:
+			if vt != math.Trunc(vt) || vt < 0 || vt >= math.MaxUint64 {
+				return fail(fmt.Errorf("%v is not an integer that fits %v", vt, dst.Type()))
+			}
+			n = uint64(vt)
+		case string:
+			var err error
+			n, err = strconv.ParseUint(vt, 10, 64)
+			if err != nil {
+				return fail(err)
+			}
+		default:
+			return mismatch()
+		}
+		if dst.OverflowUint(n) {
+			return fail(fmt.Errorf("%v overflows %v", n, dst.Type()))
+		}
+		dst.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		switch vt := v.(type) {
+		case float64:
+			f = vt
+		case string:
+			var err error
+			f, err = strconv.ParseFloat(vt, 64)
+			if err != nil {
+				return fail(err)
+			}
+		default:
+			return mismatch()
+		}
+		if dst.OverflowFloat(f) {
+			return fail(fmt.Errorf("%v overflows %v", f, dst.Type()))
+		}
+		dst.SetFloat(f)
+	case reflect.Slice:
+		a, ok := v.([]interface{})
+		if !ok {
+			return mismatch()
+		}
+		s := reflect.MakeSlice(dst.Type(), len(a), len(a))
+		ok = true
+		for i, el := range a {
+			ok = assign(s.Index(i), el, field, path+"["+strconv.Itoa(i)+"]", errs) && ok
+		}
+		if !ok {
+			return false
+		}
+		dst.Set(s)
+	case reflect.Map:
+		m, ok := v.(map[string]interface{})
+		if !ok || dst.Type().Key().Kind() != reflect.String {
+			return mismatch()
+		}
+		mv := reflect.MakeMapWithSize(dst.Type(), len(m))
+		ok = true
+		for k, el := range m {
+			ev := reflect.New(dst.Type().Elem()).Elem()
+			if assign(ev, el, field, joinPath(path, k), errs) {
+				mv.SetMapIndex(reflect.ValueOf(k).Convert(dst.Type().Key()), ev)
+			} else {
+				ok = false
+			}
+		}
+		if !ok {
+			return false
+		}
+		dst.Set(mv)
+	case reflect.Struct:
+		if _, ok := v.(map[string]interface{}); !ok {
+			return mismatch()
+		}
+		n := len(*errs)
+		extractStruct(&v, dst, field+".", path, errs)
+		return len(*errs) == n
+	default:
+		return fail(fmt.Errorf("unsupported field type %v", dst.Type()))
+	}
+	return true
+}
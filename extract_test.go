@@ -0,0 +1,154 @@
+package jsonwalk_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/zzwx/jsonwalk"
+
+	"golang.org/x/exp/slices"
+)
+
+const actorsJSON = `{
+	"Actors": [
+		{
+			"name": "Tom Cruise",
+			"age": 56,
+			"Born At": "Syracuse, NY",
+			"Birthdate": "July 3, 1962",
+			"wife": null,
+			"weight": 67.5,
+			"hasChildren": true,
+			"hasGreyHair": false,
+			"children": [
+				"Suri",
+				"Isabella Jane",
+				"Connor"
+			]
+		},
+		{
+			"name": "Robert Downey Jr.",
+			"age": 53,
+			"Born At": "New York City, NY",
+			"Birthdate": "April 4, 1965",
+			"wife": "Susan Downey",
+			"weight": 77.1,
+			"hasChildren": true,
+			"hasGreyHair": false,
+			"children": [
+				"Indio Falconer",
+				"Avri Roel",
+				"Exton Elias"
+			]
+		}
+	]
+}`
+
+func ExampleExtract() {
+	var f interface{}
+	err := json.Unmarshal([]byte(actorsJSON), &f)
+	if err != nil {
+		return
+	}
+	var actors struct {
+		First    string   `jsonwalk:"Actors[0].name,required"`
+		Age      int      `jsonwalk:"Actors[0].age"`
+		Names    []string `jsonwalk:"Actors[*].name"`
+		Children []string `jsonwalk:"Actors[1].children"`
+	}
+	err = jsonwalk.Extract(&f, &actors)
+	if err != nil {
+		return
+	}
+	fmt.Println(actors.First, actors.Age)
+	fmt.Println(actors.Names)
+	fmt.Println(actors.Children)
+	// Output:
+	// Tom Cruise 56
+	// [Tom Cruise Robert Downey Jr.]
+	// [Indio Falconer Avri Roel Exton Elias]
+}
+
+func TestExtract(t *testing.T) {
+	var f interface{}
+	err := json.Unmarshal([]byte(actorsJSON), &f)
+	if err != nil {
+		t.Errorf("error umarshalling json: %v", err)
+		return
+	}
+
+	type actor struct {
+		Name   string   `jsonwalk:"name"`
+		BornAt string   `jsonwalk:"Born At"`
+		Wife   *string  `jsonwalk:"wife"`
+		Weight float32  `jsonwalk:"weight"`
+		Age    string   `jsonwalk:"age"`
+		Kids   []string `jsonwalk:"children"`
+	}
+	var d struct {
+		Actors  []actor                `jsonwalk:"Actors"`
+		Second  *actor                 `jsonwalk:"Actors[1]"`
+		Grey    []bool                 `jsonwalk:"Actors[*].hasGreyHair"`
+		Weights []uint8                `jsonwalk:"Actors[*].weight"`
+		Raw     map[string]interface{} `jsonwalk:"Actors[0]"`
+		Ignored string                 `jsonwalk:"-"`
+		Missing string                 `jsonwalk:"Actors[2].name,required"`
+	}
+	err = jsonwalk.Extract(&f, &d)
+
+	var errs jsonwalk.ExtractErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ExtractErrors, got %v", err)
+	}
+	if len(errs) != 3 {
+		t.Errorf("expected 3 errors, got %v", errs)
+	}
+	var paths []string
+	for _, e := range errs {
+		paths = append(paths, e.Field+" "+e.Path)
+	}
+	if !slices.Equal(paths, []string{"Weights Actors[0].weight", "Weights Actors[1].weight", "Missing Actors[2].name"}) {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	if !errors.Is(errs[2], jsonwalk.ErrMissing) {
+		t.Errorf("expected a missing error, got %v", errs[2])
+	}
+
+	if len(d.Actors) != 2 || d.Actors[0].Wife != nil || d.Actors[1].Wife == nil || *d.Actors[1].Wife != "Susan Downey" {
+		t.Errorf("unexpected actors: %+v", d.Actors)
+	}
+	if d.Actors[1].BornAt != "New York City, NY" || d.Actors[1].Age != "53" || d.Actors[0].Weight != 67.5 {
+		t.Errorf("unexpected actor: %+v", d.Actors[1])
+	}
+	if d.Second == nil || d.Second.Name != "Robert Downey Jr." || slices.Compare(d.Second.Kids, d.Actors[1].Kids) != 0 {
+		t.Errorf("unexpected second actor: %+v", d.Second)
+	}
+	if !slices.Equal(d.Grey, []bool{false, false}) {
+		t.Errorf("unexpected grey hair: %v", d.Grey)
+	}
+	if d.Raw["name"] != "Tom Cruise" {
+		t.Errorf("unexpected raw map: %v", d.Raw)
+	}
+
+	var m interface{}
+	err = json.Unmarshal([]byte(`{"svc": {"d": {"port": 4}, "b": {"port": 2}, "a": {"port": 1}, "c": {"port": 3}, "e": {"port": 5}}}`), &m)
+	if err != nil {
+		t.Errorf("error umarshalling json: %v", err)
+		return
+	}
+	var ports struct {
+		Ports []int `jsonwalk:"svc.*.port"`
+	}
+	for i := 0; i < 10; i++ {
+		if err := jsonwalk.Extract(&m, &ports); err != nil || !slices.Equal(ports.Ports, []int{1, 2, 3, 4, 5}) {
+			t.Errorf("expected the ports in the order of the keys, got %v, %v", ports.Ports, err)
+			break
+		}
+	}
+
+	if err := jsonwalk.Extract(&f, d); err == nil {
+		t.Errorf("expected an error for a non-pointer destination")
+	}
+}
@@ -0,0 +1,62 @@
+package jsonwalk
+
+// PathValue is a single leaf of a flattened document.
+type PathValue struct {
+	Path  string
+	Value interface{}
+}
+
+// FlattenOptions controls the output of Flatten and FlattenMap. A nil *FlattenOptions is the same as the zero value.
+type FlattenOptions struct {
+	// Syntax of the produced paths, DottedPath by default.
+	Syntax PathSyntax
+	// Separator placed between map keys for DottedPath and EscapedPath syntax, "." if empty.
+	// It's not escaped in keys for DottedPath.
+	Separator string
+	// KeepEmpty includes empty arrays and maps as values, which otherwise leave no trace in the output.
+	KeepEmpty bool
+	// MaxIndex is the largest array index Unflatten accepts, DefaultMaxIndex if 0 or negative.
+	// It keeps a single path like "a[50000000]" from allocating a huge sparse array.
+	MaxIndex int
+}
+
+// Flatten returns every leaf of root with its path, in the order they are discovered by WalkSorted.
+//
+//	Actors[0].Born At = "Syracuse, NY"
+//	Actors[0].children[1] = "Isabella Jane"
+//
+// A root that is a single value produces a single PathValue with an empty path.
+func Flatten(root *interface{}, opts *FlattenOptions) []PathValue {
+	var o FlattenOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Separator == "" {
+		o.Separator = "."
+	}
+	var res []PathValue
+	WalkSorted(root, Callback(func(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType) {
+		switch nodeValueType {
+		case Array:
+			if !o.KeepEmpty || len(value.([]interface{})) > 0 {
+				return
+			}
+		case Map:
+			if !o.KeepEmpty || len(value.(map[string]interface{})) > 0 {
+				return
+			}
+		}
+		res = append(res, PathValue{Path: formatPath(pathSegments(path), o.Syntax, o.Separator), Value: value})
+	}))
+	return res
+}
+
+// FlattenMap does the same as Flatten but returns the leaves in a map keyed by their paths.
+func FlattenMap(root *interface{}, opts *FlattenOptions) map[string]interface{} {
+	pvs := Flatten(root, opts)
+	res := make(map[string]interface{}, len(pvs))
+	for _, pv := range pvs {
+		res[pv.Path] = pv.Value
+	}
+	return res
+}
@@ -0,0 +1,102 @@
+package jsonwalk_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/zzwx/jsonwalk"
+)
+
+func ExampleFlatten() {
+	var f interface{}
+	err := json.Unmarshal([]byte(`{"name": "Anna", "kids": ["Bo", "Cy"], "address": {"zip code": "10001"}}`), &f)
+	if err != nil {
+		return
+	}
+	for _, pv := range jsonwalk.Flatten(&f, nil) {
+		fmt.Printf("%v = %#v\n", pv.Path, pv.Value)
+	}
+	// Output:
+	// address.zip code = "10001"
+	// kids[0] = "Bo"
+	// kids[1] = "Cy"
+	// name = "Anna"
+}
+
+func ExampleWalkSorted() {
+	var f interface{}
+	err := json.Unmarshal([]byte(`{"b": 1, "a": {"d": true, "c": null}}`), &f)
+	if err != nil {
+		return
+	}
+	jsonwalk.WalkSorted(&f, jsonwalk.Print{})
+	// Output:
+	// (m)
+	// "a" |a| (s:m)
+	//   "c":<nil> |a.c| (s:n)
+	//   "d":true |a.d| (s:b)
+	// "b":1 |b| (s:f)
+}
+
+func TestFlatten(t *testing.T) {
+	var f interface{}
+	err := json.Unmarshal([]byte(`{"a.b": {"c/d": [1, {"e~f": "g"}]}, "empty": [], "none": {}, "x": null}`), &f)
+	if err != nil {
+		t.Errorf("error umarshalling json: %v", err)
+		return
+	}
+
+	for _, c := range []struct {
+		opts     *jsonwalk.FlattenOptions
+		expected []string
+	}{
+		{nil, []string{`a.b.c/d[0]=1`, `a.b.c/d[1].e~f="g"`, `x=<nil>`}},
+		{&jsonwalk.FlattenOptions{Syntax: jsonwalk.EscapedPath}, []string{`a\.b.c/d[0]=1`, `a\.b.c/d[1].e~f="g"`, `x=<nil>`}},
+		{&jsonwalk.FlattenOptions{Syntax: jsonwalk.EscapedPath, Separator: "/"}, []string{`a.b/c\/d[0]=1`, `a.b/c\/d[1]/e~f="g"`, `x=<nil>`}},
+		{&jsonwalk.FlattenOptions{Syntax: jsonwalk.PointerPath}, []string{`/a.b/c~1d/0=1`, `/a.b/c~1d/1/e~0f="g"`, `/x=<nil>`}},
+		{&jsonwalk.FlattenOptions{Separator: "__", KeepEmpty: true}, []string{`a.b__c/d[0]=1`, `a.b__c/d[1]__e~f="g"`, `empty=[]interface {}{}`, `none=map[string]interface {}{}`, `x=<nil>`}},
+	} {
+		var got []string
+		for _, pv := range jsonwalk.Flatten(&f, c.opts) {
+			got = append(got, fmt.Sprintf("%v=%#v", pv.Path, pv.Value))
+		}
+		if fmt.Sprint(got) != fmt.Sprint(c.expected) {
+			t.Errorf("options %+v: expected %v, got %v", c.opts, c.expected, got)
+		}
+	}
+
+	var root interface{} = "abc"
+	m := jsonwalk.FlattenMap(&root, nil)
+	if len(m) != 1 || m[""] != "abc" {
+		t.Errorf("unexpected flattened root value: %v", m)
+	}
+}
+
+func TestFormatPath(t *testing.T) {
+	var f interface{}
+	err := json.Unmarshal([]byte(`{"a/b": [{"c.d": 1}]}`), &f)
+	if err != nil {
+		t.Errorf("error umarshalling json: %v", err)
+		return
+	}
+	found := false
+	jsonwalk.Walk(&f, jsonwalk.Callback(func(path jsonwalk.WalkPath, key, value interface{}, vType jsonwalk.NodeValueType) {
+		if vType != jsonwalk.Float64 {
+			return
+		}
+		found = true
+		for syntax, expected := range map[jsonwalk.PathSyntax]string{
+			jsonwalk.DottedPath:  `a/b[0].c.d`,
+			jsonwalk.EscapedPath: `a/b[0].c\.d`,
+			jsonwalk.PointerPath: `/a~1b/0/c.d`,
+		} {
+			if got := jsonwalk.FormatPath(path, syntax); got != expected {
+				t.Errorf("expected %v, got %v", expected, got)
+			}
+		}
+	}))
+	if !found {
+		t.Errorf("leaf not found")
+	}
+}
@@ -3,3 +3,5 @@ module github.com/zzwx/jsonwalk
 go 1.19
 
 require golang.org/x/exp v0.0.0-20221114191408-850992195362
+
+require gopkg.in/yaml.v3 v3.0.1
@@ -0,0 +1,210 @@
+package jsonwalk
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// GoOptions configures GoTypes. A nil *GoOptions is the same as the zero value.
+type GoOptions struct {
+	// Package is the name of the package clause to start the source with. There's no package clause if it's empty.
+	Package string
+	// Name is the name of the root type, "Root" if empty.
+	Name string
+}
+
+// GoTypes returns the gofmt-ed Go type declarations that the documents described by s can be unmarshalled into:
+//
+//	s := jsonwalk.InferSchema(&sample1, &sample2)
+//	src, err := jsonwalk.GoTypes(s, &jsonwalk.GoOptions{Package: "api", Name: "Response"})
+//
+// Maps become structs with a field for every member, in the sorted order, with the json tags
+// having ",omitempty" for Optional members. Members whose key can't be a json tag, such as "" or "a'b",
+// are left out with a comment. Structs for nested Maps are declared as separate types named after
+// their field, with the trailing "s" dropped for the elements of arrays: "Actors" holds a []Actor.
+//
+// Arrays become slices, numbers become int64 if none of them had a fractional part or float64 otherwise,
+// values that were null in some of the samples, as well as optional structs, become pointers
+// and values of different types become interface{}.
+func GoTypes(s *Schema, opts *GoOptions) ([]byte, error) {
+	g := goGen{names: map[string]bool{}}
+	if opts != nil {
+		g.opts = *opts
+	}
+	name := g.opts.Name
+	if name == "" {
+		name = "Root"
+	}
+	var b bytes.Buffer
+	if g.opts.Package != "" {
+		_, _ = fmt.Fprintf(&b, "package %v\n\n", g.opts.Package)
+	}
+	g.names[name] = true
+	g.declare(name, s, false)
+	for _, d := range g.decls {
+		b.WriteString(d)
+	}
+	return format.Source(b.Bytes())
+}
+
+type goGen struct {
+	opts  GoOptions
+	names map[string]bool // declared type names
+	decls []string
+}
+
+// declare adds the declaration of type name for the nodes described by s.
+// If isStruct is set, it's declared as a struct even if some of the nodes were null.
+func (g *goGen) declare(name string, s *Schema, isStruct bool) {
+	i := len(g.decls)
+	g.decls = append(g.decls, "") // reserve the place, so that nested types follow
+	if isStruct || len(s.Types) == 1 && s.Types[Map] > 0 && len(s.Properties) > 0 {
+		g.decls[i] = fmt.Sprintf("type %v %v\n\n", name, g.structType(name, s))
+		return
+	}
+	g.decls[i] = fmt.Sprintf("type %v %v\n\n", name, g.goType(name, name, s))
+}
+
+func (g *goGen) structType(name string, s *Schema) string {
+	var b strings.Builder
+	b.WriteString("struct {\n")
+	fields := map[string]bool{}
+	keys := maps.Keys(s.Properties)
+	slices.Sort(keys)
+	for _, k := range keys {
+		// encoding/json falls back to the field name for an empty or invalid tag name.
+		if k == "" || !validTagName(k) {
+			_, _ = fmt.Fprintf(&b, "// Member %v can't be expressed with a json tag.\n", strconv.Quote(k))
+			continue
+		}
+		p := s.Properties[k]
+		field := unique(goIdentifier(k), fields)
+		tag := k
+		if p.Optional {
+			tag += ",omitempty"
+		} else if k == "-" {
+			tag += "," // a bare "-" tag skips the field
+		}
+		gt := g.goType(name, field, p)
+		if p.Optional && g.names[gt] {
+			gt = "*" + gt // omitempty doesn't omit structs
+		}
+		_, _ = fmt.Fprintf(&b, "%v %v `json:\"%v\"`\n", field, gt, tag)
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// validTagName reports whether encoding/json accepts k as the name in a json tag.
+func validTagName(k string) bool {
+	for _, c := range k {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", c):
+		case !unicode.IsLetter(c) && !unicode.IsDigit(c):
+			return false
+		}
+	}
+	return true
+}
+
+// goType returns the Go type for the nodes described by s, found in a field of type parent.
+func (g *goGen) goType(parent, field string, s *Schema) string {
+	types := maps.Keys(s.Types)
+	slices.Sort(types)
+	nullable := false
+	if len(types) > 1 && types[0] == Nil {
+		nullable = true
+		types = types[1:]
+	}
+	if len(types) != 1 || types[0] == Nil {
+		return "interface{}"
+	}
+	var gt string
+	switch types[0] {
+	case Bool:
+		gt = "bool"
+	case String:
+		gt = "string"
+	case Float64:
+		gt = "float64"
+		if s.Integers == s.Types[Float64] {
+			gt = "int64"
+		}
+	case Array:
+		if s.Items == nil {
+			return "[]interface{}"
+		}
+		return "[]" + g.goType(parent, singular(field), s.Items)
+	case Map:
+		if len(s.Properties) == 0 {
+			return "map[string]interface{}"
+		}
+		name := field
+		if g.names[name] {
+			name = parent + field
+		}
+		name = unique(name, g.names)
+		g.declare(name, s, true)
+		gt = name
+	}
+	if nullable {
+		return "*" + gt
+	}
+	return gt
+}
+
+// unique returns name, or name with the smallest numeric suffix starting with 2 that isn't in taken,
+// and adds it to taken.
+func unique(name string, taken map[string]bool) string {
+	u := name
+	for i := 2; taken[u]; i++ {
+		u = name + strconv.Itoa(i)
+	}
+	taken[u] = true
+	return u
+}
+
+// singular returns the name for an element of an array field.
+func singular(field string) string {
+	if len(field) > 3 && strings.HasSuffix(field, "s") &&
+		!strings.HasSuffix(field, "ss") && !strings.HasSuffix(field, "us") && !strings.HasSuffix(field, "is") {
+		return field[:len(field)-1]
+	}
+	return field + "Item"
+}
+
+// goInitialisms are the words written in upper case in Go identifiers.
+var goInitialisms = map[string]bool{
+	"API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true, "HTML": true, "HTTP": true, "HTTPS": true,
+	"ID": true, "IP": true, "JSON": true, "SQL": true, "SSH": true, "TCP": true, "TLS": true, "UI": true,
+	"URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+// goIdentifier returns an exported Go identifier for Map key k: "Born At" is BornAt, "user_id" is UserID.
+func goIdentifier(k string) string {
+	var b strings.Builder
+	for _, w := range strings.FieldsFunc(k, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		if goInitialisms[strings.ToUpper(w)] {
+			b.WriteString(strings.ToUpper(w))
+			continue
+		}
+		r := []rune(w)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+	id := b.String()
+	if id == "" {
+		return "Field"
+	}
+	if r := []rune(id); !unicode.IsUpper(r[0]) {
+		return "F" + id
+	}
+	return id
+}
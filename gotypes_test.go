@@ -0,0 +1,72 @@
+package jsonwalk_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/zzwx/jsonwalk"
+)
+
+func ExampleGoTypes() {
+	var a, b interface{}
+	err := json.Unmarshal([]byte(`{"user_id": 1, "name": "Tom", "address": {"city": "Syracuse"}, "tags": ["a"]}`), &a)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal([]byte(`{"user_id": 2, "name": null, "score": 4.5, "friends": [{"user_id": 1, "address": {"zip": "13201"}}]}`), &b)
+	if err != nil {
+		return
+	}
+	src, err := jsonwalk.GoTypes(jsonwalk.InferSchema(&a, &b), &jsonwalk.GoOptions{Name: "User"})
+	if err != nil {
+		return
+	}
+	fmt.Print(string(src))
+	// Output:
+	// type User struct {
+	// 	Address *Address `json:"address,omitempty"`
+	// 	Friends []Friend `json:"friends,omitempty"`
+	// 	Name    *string  `json:"name"`
+	// 	Score   float64  `json:"score,omitempty"`
+	// 	Tags    []string `json:"tags,omitempty"`
+	// 	UserID  int64    `json:"user_id"`
+	// }
+	//
+	// type Address struct {
+	// 	City string `json:"city"`
+	// }
+	//
+	// type Friend struct {
+	// 	Address FriendAddress `json:"address"`
+	// 	UserID  int64         `json:"user_id"`
+	// }
+	//
+	// type FriendAddress struct {
+	// 	Zip string `json:"zip"`
+	// }
+}
+
+func TestGoTypes(t *testing.T) {
+	var f interface{}
+	err := json.Unmarshal([]byte(`[{"Status": "ok", "1st": true, "mixed": [1, "a"], "": {}, "-": 1, "a'b": 2, "items": [[1.5]]}]`), &f)
+	if err != nil {
+		t.Errorf("error umarshalling json: %v", err)
+		return
+	}
+	src, err := jsonwalk.GoTypes(jsonwalk.InferSchema(&f), &jsonwalk.GoOptions{Package: "api"})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	expected := "package api\n\ntype Root []RootItem\n\ntype RootItem struct {\n" +
+		"\t// Member \"\" can't be expressed with a json tag.\n" +
+		"\tField  int64  `json:\"-,\"`\n" +
+		"\tF1st   bool   `json:\"1st\"`\n" +
+		"\tStatus string `json:\"Status\"`\n" +
+		"\t// Member \"a'b\" can't be expressed with a json tag.\n" +
+		"\tItems [][]float64   `json:\"items\"`\n" +
+		"\tMixed []interface{} `json:\"mixed\"`\n}\n"
+	if string(src) != expected {
+		t.Errorf("expected:\n%v\ngot:\n%v", expected, string(src))
+	}
+}
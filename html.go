@@ -0,0 +1,101 @@
+package jsonwalk
+
+import (
+	"html"
+	"io"
+	"strings"
+)
+
+// htmlWriter implements WalkLeaveCallback by rendering the walked document as nested HTML tables and lists.
+type htmlWriter struct {
+	w    io.Writer
+	open []NodeValueType // types of the open containers
+	err  error
+}
+
+// NewHTMLWriter returns a WalkLeaveCallback that renders the walked document to w as HTML:
+// Maps become tables with a row for every member, the key in its head cell, Arrays become lists
+// numbered from 0 and leaves are written as escaped text, nested within each other:
+//
+//	<table>
+//	<tr><th>Actors</th><td><ol start="0">
+//	<li><table>
+//	<tr><th>age</th><td>56</td></tr>
+//	...
+//
+// Containers are opened in C and closed in L, so the callback has to be passed to Walk directly
+// or through the callbacks that forward L, such as Multi, Filter or Limit.
+func NewHTMLWriter(w io.Writer) *htmlWriter {
+	return &htmlWriter{w: w}
+}
+
+// Err returns the first error that occurred while writing. Nothing is written after an error.
+func (hw *htmlWriter) Err() error {
+	return hw.err
+}
+
+func (hw *htmlWriter) C(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType) {
+	if hw.err != nil || len(hw.open) != path.Level() {
+		return // not under the innermost open container, such as under a container Limit didn't pass
+	}
+	var b strings.Builder
+	if len(hw.open) > 0 {
+		if hw.open[len(hw.open)-1] == Map {
+			b.WriteString("<tr><th>" + html.EscapeString(key.(string)) + "</th><td>")
+		} else {
+			b.WriteString("<li>")
+		}
+	}
+	switch nodeValueType {
+	case Map:
+		b.WriteString("<table>\n")
+		hw.open = append(hw.open, Map)
+	case Array:
+		b.WriteString("<ol start=\"0\">\n")
+		hw.open = append(hw.open, Array)
+	default:
+		if value == nil {
+			b.WriteString("null")
+		} else {
+			b.WriteString(html.EscapeString(tableCell(value)))
+		}
+		b.WriteString(hw.closing(len(hw.open)))
+	}
+	hw.write(b.String())
+}
+
+func (hw *htmlWriter) L(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType) {
+	if hw.err != nil || len(hw.open) != path.Level()+1 {
+		return
+	}
+	s := "</ol>"
+	if hw.open[len(hw.open)-1] == Map {
+		s = "</table>"
+	}
+	hw.open = hw.open[:len(hw.open)-1]
+	hw.write(s + hw.closing(len(hw.open)))
+}
+
+// closing returns the end of the child of a container open at the level, or a newline for the root.
+func (hw *htmlWriter) closing(level int) string {
+	switch {
+	case level == 0:
+		return "\n"
+	case hw.open[level-1] == Map:
+		return "</td></tr>\n"
+	}
+	return "</li>\n"
+}
+
+func (hw *htmlWriter) write(s string) {
+	if _, err := io.WriteString(hw.w, s); err != nil {
+		hw.err = err
+	}
+}
+
+// WriteHTML renders the tree at root to w with NewHTMLWriter, walking it with WalkSorted.
+func WriteHTML(w io.Writer, root *interface{}) error {
+	hw := NewHTMLWriter(w)
+	WalkSorted(root, hw)
+	return hw.Err()
+}
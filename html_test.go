@@ -0,0 +1,84 @@
+package jsonwalk_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/zzwx/jsonwalk"
+)
+
+func ExampleWriteHTML() {
+	var f interface{}
+	err := json.Unmarshal([]byte(`{"name": "Tom <Cruise>", "children": ["Suri", {"age": 12}], "wife": null}`), &f)
+	if err != nil {
+		return
+	}
+	_ = jsonwalk.WriteHTML(os.Stdout, &f)
+	// Output:
+	// <table>
+	// <tr><th>children</th><td><ol start="0">
+	// <li>Suri</li>
+	// <li><table>
+	// <tr><th>age</th><td>12</td></tr>
+	// </table></li>
+	// </ol></td></tr>
+	// <tr><th>name</th><td>Tom &lt;Cruise&gt;</td></tr>
+	// <tr><th>wife</th><td>null</td></tr>
+	// </table>
+}
+
+func ExampleTable_WriteMarkdown() {
+	var f interface{}
+	err := json.Unmarshal([]byte(`[{"name": "a|b", "note": "*bold*\nline"}, {"name": "c"}]`), &f)
+	if err != nil {
+		return
+	}
+	tbl, err := jsonwalk.ToTable(&f, "", nil)
+	if err != nil {
+		return
+	}
+	_ = tbl.WriteMarkdown(os.Stdout)
+	_ = jsonwalk.PathTable(&f, nil).WriteMarkdown(os.Stdout)
+	// Output:
+	// | name | note |
+	// | --- | --- |
+	// | a\|b | \*bold\*<br>line |
+	// | c |  |
+	// | Path | Value |
+	// | --- | --- |
+	// | \[0\].name | a\|b |
+	// | \[0\].note | \*bold\*<br>line |
+	// | \[1\].name | c |
+}
+
+func TestTableHTML(t *testing.T) {
+	var f interface{}
+	err := json.Unmarshal([]byte(`[{"a": "<&>", "b": 1}, {"b": true}]`), &f)
+	if err != nil {
+		t.Errorf("error umarshalling json: %v", err)
+		return
+	}
+	tbl, err := jsonwalk.ToTable(&f, "", nil)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	var b bytes.Buffer
+	_ = tbl.WriteHTML(&b)
+	expected := "<table>\n<thead>\n<tr><th>a</th><th>b</th></tr>\n</thead>\n<tbody>\n" +
+		"<tr><td>&lt;&amp;&gt;</td><td>1</td></tr>\n<tr><td></td><td>true</td></tr>\n</tbody>\n</table>\n"
+	if b.String() != expected {
+		t.Errorf("expected:\n%v\ngot:\n%v", expected, b.String())
+	}
+
+	b.Reset()
+	var s interface{} = "<x>"
+	_ = jsonwalk.WriteHTML(&b, &s)
+	jsonwalk.WalkSorted(&f, jsonwalk.Limit(2, jsonwalk.NewHTMLWriter(&b)))
+	expected = "&lt;x&gt;\n<ol start=\"0\">\n<li><table>\n</table></li>\n</ol>\n"
+	if b.String() != expected {
+		t.Errorf("expected:\n%v\ngot:\n%v", expected, b.String())
+	}
+}
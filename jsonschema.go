@@ -0,0 +1,105 @@
+package jsonwalk
+
+import (
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// JSONSchemaDialect is the "$schema" of the documents returned by Schema.JSONSchema.
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema returns s as a JSON Schema (draft 2020-12) document, ready to be marshalled or walked:
+//
+//	b, err := json.MarshalIndent(jsonwalk.InferSchema(&doc1, &doc2).JSONSchema(), "", "  ")
+//
+// Maps become objects with "properties" and "required" for the members that aren't Optional,
+// Arrays get "items" and nodes of several types get a "type" array. Numbers are "integer"
+// if none of them had a fractional part, and strings get a "format" if all of them were in the same format:
+// "date-time" (RFC 3339), "uri" (absolute) or "email". Examples are kept as "examples".
+func (s *Schema) JSONSchema() interface{} {
+	root := s.jsonSchema()
+	root["$schema"] = JSONSchemaDialect
+	return root
+}
+
+func (s *Schema) jsonSchema() map[string]interface{} {
+	js := map[string]interface{}{}
+	types := maps.Keys(s.Types)
+	slices.Sort(types)
+	var names []interface{}
+	for _, nt := range types {
+		names = append(names, s.jsonSchemaType(nt))
+	}
+	switch len(names) {
+	case 0:
+	case 1:
+		js["type"] = names[0]
+	default:
+		js["type"] = names
+	}
+	if s.Types[String] > 0 {
+		for format, n := range s.Formats {
+			if n == s.Types[String] {
+				js["format"] = format
+			}
+		}
+	}
+	if s.Types[Map] > 0 && len(s.Properties) > 0 {
+		properties := map[string]interface{}{}
+		var required []interface{}
+		keys := maps.Keys(s.Properties)
+		slices.Sort(keys)
+		for _, k := range keys {
+			p := s.Properties[k]
+			properties[k] = p.jsonSchema()
+			if !p.Optional {
+				required = append(required, k)
+			}
+		}
+		js["properties"] = properties
+		if len(required) > 0 {
+			js["required"] = required
+		}
+	}
+	if s.Items != nil {
+		js["items"] = s.Items.jsonSchema()
+	}
+	if len(s.Examples) > 0 {
+		js["examples"] = deepCopy(s.Examples)
+	}
+	return js
+}
+
+func (s *Schema) jsonSchemaType(nt NodeValueType) string {
+	if nt == Float64 && s.Integers == s.Types[Float64] {
+		return "integer"
+	}
+	return typeName(nt)
+}
+
+// opaqueSchemes are the URI schemes detected by stringFormat without a host, as in "urn:isbn:0451450523".
+var opaqueSchemes = map[string]bool{"mailto": true, "urn": true, "tel": true, "data": true}
+
+// stringFormat returns the JSON Schema format of s, or an empty string if it's not in any of the detected formats.
+func stringFormat(s string) string {
+	if _, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return "date-time"
+	}
+	if strings.ContainsAny(s, " \t\r\n") {
+		return ""
+	}
+	// Values like "nginx:latest", "localhost:8080" or "sha256:4a1d…" parse as URIs with an opaque part,
+	// so only the schemes known to have one are accepted without a host.
+	if u, err := url.Parse(s); err == nil && u.Scheme != "" && (u.Host != "" || u.Opaque != "" && opaqueSchemes[u.Scheme]) {
+		return "uri"
+	}
+	if a, err := mail.ParseAddress(s); err == nil && a.Address == s && a.Name == "" {
+		return "email"
+	}
+	return ""
+}
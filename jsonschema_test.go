@@ -0,0 +1,112 @@
+package jsonwalk_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/zzwx/jsonwalk"
+)
+
+func ExampleSchema_JSONSchema() {
+	var a, b interface{}
+	err := json.Unmarshal([]byte(`{"id": 1, "email": "tom@example.com", "site": "https://example.com", "at": "2019-10-12T07:20:50.52Z", "score": 4.5}`), &a)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal([]byte(`{"id": 2, "email": "robert@example.com", "score": 5, "tags": ["a", null]}`), &b)
+	if err != nil {
+		return
+	}
+	js, _ := json.MarshalIndent(jsonwalk.InferSchema(&a, &b).JSONSchema(), "", "  ")
+	fmt.Println(string(js))
+	// Output:
+	// {
+	//   "$schema": "https://json-schema.org/draft/2020-12/schema",
+	//   "properties": {
+	//     "at": {
+	//       "examples": [
+	//         "2019-10-12T07:20:50.52Z"
+	//       ],
+	//       "format": "date-time",
+	//       "type": "string"
+	//     },
+	//     "email": {
+	//       "examples": [
+	//         "tom@example.com",
+	//         "robert@example.com"
+	//       ],
+	//       "format": "email",
+	//       "type": "string"
+	//     },
+	//     "id": {
+	//       "examples": [
+	//         1,
+	//         2
+	//       ],
+	//       "type": "integer"
+	//     },
+	//     "score": {
+	//       "examples": [
+	//         4.5,
+	//         5
+	//       ],
+	//       "type": "number"
+	//     },
+	//     "site": {
+	//       "examples": [
+	//         "https://example.com"
+	//       ],
+	//       "format": "uri",
+	//       "type": "string"
+	//     },
+	//     "tags": {
+	//       "items": {
+	//         "examples": [
+	//           "a",
+	//           null
+	//         ],
+	//         "type": [
+	//           "null",
+	//           "string"
+	//         ]
+	//       },
+	//       "type": "array"
+	//     }
+	//   },
+	//   "required": [
+	//     "email",
+	//     "id",
+	//     "score"
+	//   ],
+	//   "type": "object"
+	// }
+}
+
+func TestJSONSchemaFormats(t *testing.T) {
+	for _, test := range []struct {
+		values   string
+		expected interface{}
+	}{
+		{`["2006-01-02T15:04:05+07:00", "2006-01-02T15:04:05Z"]`, "date-time"},
+		{`["2006-01-02", "2006-01-02T15:04:05Z"]`, nil},
+		{`["urn:isbn:0451450523", "http://example.com/a?b=c"]`, "uri"},
+		{`["/relative/path"]`, nil},
+		{`["sha256:4a1d0c2f5e", "nginx:latest", "localhost:8080"]`, nil},
+		{`["nginx:latest"]`, nil},
+		{`["mailto:tom@example.com", "https://localhost:8080"]`, "uri"},
+		{`["a.b@example.com"]`, "email"},
+		{`["Tom <tom@example.com>"]`, nil},
+		{`["plain text"]`, nil},
+	} {
+		var f interface{}
+		if err := json.Unmarshal([]byte(test.values), &f); err != nil {
+			t.Errorf("error umarshalling json: %v", err)
+			continue
+		}
+		js := jsonwalk.InferSchema(&f).JSONSchema().(map[string]interface{})
+		if got := js["items"].(map[string]interface{})["format"]; got != test.expected {
+			t.Errorf("%v: expected format %v, got %v", test.values, test.expected, got)
+		}
+	}
+}
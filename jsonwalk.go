@@ -7,6 +7,9 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 type NodeValueType int
@@ -37,7 +40,10 @@ type walkPath struct {
 	parent      *walkPath // nil parent for the first node
 	portion     string
 	preSepErase bool
-	level       int // origin has the level of 0
+	seg         pathSeg // unrendered key or index of the node
+	level       int     // origin has the level of 0
+	doc         int     // index+1 of the document walked by WalkStream, 0 for other walks
+	line        int     // line number of the record walked by WalkLines, 0 for other walks
 }
 
 func newWalkPath() walkPath {
@@ -70,7 +76,10 @@ func (w walkPath) MapEl(k string) walkPath {
 		parent:      &w,
 		portion:     k,
 		preSepErase: false,
+		seg:         pathSeg{key: k},
 		level:       w.level + 1,
+		doc:         w.doc,
+		line:        w.line,
 	}
 	return n
 }
@@ -80,7 +89,10 @@ func (w walkPath) ArrayEl(i int) walkPath {
 		parent:      &w,
 		portion:     "[" + strconv.Itoa(i) + "]",
 		preSepErase: true,
+		seg:         pathSeg{index: i, isIndex: true},
 		level:       w.level + 1,
+		doc:         w.doc,
+		line:        w.line,
 	}
 	return n
 }
@@ -89,6 +101,16 @@ func (w walkPath) Level() int {
 	return w.level
 }
 
+func (w walkPath) segments() []pathSeg {
+	segs := make([]pathSeg, w.level)
+	for v := &w; v != nil && v.parent != nil; v = v.parent {
+		if v.level > 0 && v.level <= len(segs) {
+			segs[v.level-1] = v.seg
+		}
+	}
+	return segs
+}
+
 // WalkCallback is an interface with a callback function that is called for both leaf nodes of types
 // Nil ("null" in JSON terminology),
 // Bool,
@@ -112,6 +134,16 @@ type WalkCallback interface {
 	C(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType)
 }
 
+// WalkLeaveCallback is a WalkCallback that is also notified when the walk leaves a container node.
+//
+// WalkLeaveCallback.L is called for every node of type Array or Map with the same arguments C was called with,
+// after all the children of the node have been discovered. Together C and L give an enter/leave lifecycle
+// for the containers, which is what writers producing nested output need.
+type WalkLeaveCallback interface {
+	WalkCallback
+	L(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType)
+}
+
 type f struct {
 	f func(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType)
 }
@@ -125,20 +157,44 @@ func Callback(c func(path WalkPath, key interface{}, value interface{}, nodeValu
 	return f{c}
 }
 
+type fl struct {
+	f
+	l func(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType)
+}
+
+func (fl fl) L(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType) {
+	fl.l(path, key, value, nodeValueType)
+}
+
+// LeaveCallback is a wrapper that accepts a callback function c called for every discovered node
+// and a callback function l called when leaving every container node, and returns a value
+// that satisfies the WalkLeaveCallback interface.
+func LeaveCallback(c, l func(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType)) WalkLeaveCallback {
+	return fl{f: f{c}, l: l}
+}
+
 // Print implements WalkCallback by printing JSON to the os.Stdout by utilizing the NewOutput(os.Stdout).
 //
 // Passing Print{} to the Walk function is enough to start printing the JSON structure.
+// Print{Color: true} colorizes the output with the DefaultTheme if os.Stdout is a terminal,
+// as NewColorOutput(os.Stdout, ColorAuto, nil) does.
 type Print struct {
+	Color bool
 }
 
-func (Print) C(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType) {
+func (p Print) C(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType) {
+	if p.Color {
+		NewColorOutput(os.Stdout, ColorAuto, nil).C(path, key, value, nodeValueType)
+		return
+	}
 	NewOutput(os.Stdout).C(path, key, value, nodeValueType)
 }
 
 // output implements WalkCallback in a form of a simple tree-like structure
 // good enough for debugging. NewOutput allows to specify the output.
 type output struct {
-	w io.Writer
+	w     io.Writer
+	theme *Theme // nil for the plain output
 }
 
 // NewOutput returns an Output object initialized with the io.Writer output.
@@ -189,26 +245,33 @@ func (o *output) C(path WalkPath, key interface{}, value interface{}, nodeValueT
 	}
 	levelStr := ""
 	//levelStr = fmt.Sprintf(" #%d", path.Level())
+	var th Theme // the zero Theme doesn't color anything
+	if o.theme != nil {
+		th = *o.theme
+	}
+	typeStr := strings.ToLower(nodeValueType.String()[:1])
 	if nodeValueType == Array || nodeValueType == Map {
 		// Not a leaf, only dealing with the key
 		if path.Level() == 0 {
 			// For root map / array we simply output its type.
-			_, _ = fmt.Fprintf(o.w, "(%v)%v\n",
-				strings.ToLower(nodeValueType.String()[:1]), levelStr)
+			_, _ = fmt.Fprintf(o.w, "%v%v\n",
+				th.paint(th.Type, "("+typeStr+")"), levelStr)
 		} else {
 			var lv = strings.Repeat("  ", level)
-			_, _ = fmt.Fprintf(o.w, "%v%#v |%v| (%v:%v)%v\n",
-				lv, key, strings.TrimSpace(path.Path()), keyType, strings.ToLower(nodeValueType.String()[:1]), levelStr)
+			_, _ = fmt.Fprintf(o.w, "%v%v %v %v%v\n",
+				lv, th.paint(th.Key, fmt.Sprintf("%#v", key)), th.paint(th.Path, "|"+strings.TrimSpace(path.Path())+"|"),
+				th.paint(th.Type, "("+keyType+":"+typeStr+")"), levelStr)
 		}
 	} else {
 		if path.Level() == 0 {
 			// For root single value we simply output its value and type.
-			_, _ = fmt.Fprintf(o.w, "%#v (%v)%v\n",
-				value, strings.ToLower(nodeValueType.String()[:1]), levelStr)
+			_, _ = fmt.Fprintf(o.w, "%v %v%v\n",
+				th.paintValue(value, nodeValueType), th.paint(th.Type, "("+typeStr+")"), levelStr)
 		} else {
 			var lv = strings.Repeat("  ", level)
-			_, _ = fmt.Fprintf(o.w, "%v%#v:%#v |%v| (%v:%v)%v\n",
-				lv, key, value, strings.TrimSpace(path.Path()), keyType, strings.ToLower(nodeValueType.String()[:1]), levelStr)
+			_, _ = fmt.Fprintf(o.w, "%v%v:%v %v %v%v\n",
+				lv, th.paint(th.Key, fmt.Sprintf("%#v", key)), th.paintValue(value, nodeValueType),
+				th.paint(th.Path, "|"+strings.TrimSpace(path.Path())+"|"), th.paint(th.Type, "("+keyType+":"+typeStr+")"), levelStr)
 		}
 		return
 	}
@@ -234,7 +297,13 @@ func (o *output) C(path WalkPath, key interface{}, value interface{}, nodeValueT
 //	  ...
 //	}))
 func Walk(m *interface{}, walk WalkCallback) {
-	w(newWalkPath(), nil, m, walk)
+	w(newWalkPath(), nil, m, walk, false)
+}
+
+// WalkSorted does the same as Walk except that Map keys are discovered in the sorted order,
+// making the sequence of calls to walk.C reproducible.
+func WalkSorted(m *interface{}, walk WalkCallback) {
+	w(newWalkPath(), nil, m, walk, true)
 }
 
 // WalkWith does the same as Walk except that it accepts starting WalkPath value
@@ -246,7 +315,7 @@ func WalkWith(path WalkPath, m *interface{}, walk WalkCallback) {
 	if path == nil {
 		Walk(m, walk)
 	} else {
-		w(path, nil, m, walk)
+		w(path, nil, m, walk, false)
 	}
 }
 
@@ -269,7 +338,7 @@ func t(k interface{}) NodeValueType {
 	}
 }
 
-func w(path WalkPath, k interface{}, v *interface{}, walk WalkCallback) {
+func w(path WalkPath, k interface{}, v *interface{}, walk WalkCallback, sorted bool) {
 	switch vt := (*v).(type) {
 	case nil:
 		if walk != nil {
@@ -291,25 +360,40 @@ func w(path WalkPath, k interface{}, v *interface{}, walk WalkCallback) {
 		if walk != nil {
 			walk.C(path, k, vt, Array)
 		}
-		arrayWalk(path, &vt, walk)
+		arrayWalk(path, &vt, walk, sorted)
+		if l, ok := walk.(WalkLeaveCallback); ok {
+			l.L(path, k, vt, Array)
+		}
 	case map[string]interface{}:
 		if walk != nil {
 			walk.C(path, k, vt, Map)
 		}
-		mapWalk(path, &vt, walk)
+		mapWalk(path, &vt, walk, sorted)
+		if l, ok := walk.(WalkLeaveCallback); ok {
+			l.L(path, k, vt, Map)
+		}
 	default:
 		panic(fmt.Sprintf("%v=%v (unknown type %v)", k, vt, reflect.TypeOf(vt)))
 	}
 }
 
-func mapWalk(path WalkPath, m *map[string]interface{}, walk WalkCallback) {
+func mapWalk(path WalkPath, m *map[string]interface{}, walk WalkCallback, sorted bool) {
+	if sorted {
+		keys := maps.Keys(*m)
+		slices.Sort(keys)
+		for _, k := range keys {
+			v := (*m)[k]
+			w(path.MapEl(k), k, &v, walk, sorted)
+		}
+		return
+	}
 	for k, v := range *m {
-		w(path.MapEl(k), k, &v, walk)
+		w(path.MapEl(k), k, &v, walk, sorted)
 	}
 }
 
-func arrayWalk(path WalkPath, a *[]interface{}, walk WalkCallback) {
+func arrayWalk(path WalkPath, a *[]interface{}, walk WalkCallback, sorted bool) {
 	for i, v := range *a {
-		w(path.ArrayEl(i), i, &v, walk)
+		w(path.ArrayEl(i), i, &v, walk, sorted)
 	}
 }
@@ -0,0 +1,176 @@
+package jsonwalk
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// JSONWriterOptions configures NewJSONWriter and WriteJSON. A nil *JSONWriterOptions is the same as the zero value.
+type JSONWriterOptions struct {
+	// Indent is repeated for every level of nesting. The output is compact if it's empty.
+	Indent string
+	// EscapeHTML escapes "<", ">" and "&" in strings, the way json.Marshal does.
+	EscapeHTML bool
+	// SortKeys makes WriteJSON write Map members in the sorted order, otherwise they are written in the order
+	// Walk discovers them, which is unpredictable: the original order of the keys in the JSON text is lost
+	// once it's decoded into a map[string]interface{}, so it can't be kept.
+	// NewJSONWriter writes them in the order they are discovered, which is sorted when walked by WalkSorted.
+	SortKeys bool
+	// Filter drops the nodes, along with all their children, for which it returns false.
+	// Dropping the root leaves the output empty.
+	Filter func(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType) bool
+}
+
+// jsonWriter implements WalkLeaveCallback by writing the walked document as JSON.
+type jsonWriter struct {
+	w    io.Writer
+	opts JSONWriterOptions
+	open []int // number of children written so far for every open container
+	skip int   // level of the dropped node which children are being skipped, or -1
+	err  error
+}
+
+// NewJSONWriter returns a WalkLeaveCallback that writes the walked document to w as JSON,
+// followed by a newline:
+//
+//	jw := jsonwalk.NewJSONWriter(os.Stdout, &jsonwalk.JSONWriterOptions{
+//		Indent: "  ",
+//		Filter: func(path jsonwalk.WalkPath, key interface{}, value interface{}, nodeValueType jsonwalk.NodeValueType) bool {
+//			return key != "password"
+//		},
+//	})
+//	jsonwalk.WalkSorted(&f, jw)
+//	if err := jw.Err(); err != nil {
+//		return err
+//	}
+//
+// Containers are opened in C and closed in L, so the callback has to be passed to Walk directly
+// or through the callbacks that forward L, such as Multi, Filter or Limit.
+// The same writer can be used for several walks, each one producing a separate document.
+func NewJSONWriter(w io.Writer, opts *JSONWriterOptions) *jsonWriter {
+	jw := &jsonWriter{w: w, skip: -1}
+	if opts != nil {
+		jw.opts = *opts
+	}
+	return jw
+}
+
+// Err returns the first error that occurred while writing, such as a failed write or a number that
+// can't be represented in JSON. Nothing is written after an error.
+func (jw *jsonWriter) Err() error {
+	return jw.err
+}
+
+func (jw *jsonWriter) C(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType) {
+	level := path.Level()
+	if jw.err != nil || jw.skip >= 0 && level > jw.skip {
+		return
+	}
+	jw.skip = -1
+	if len(jw.open) != level {
+		return // not under the innermost open container, such as under a container Filter didn't pass
+	}
+	if jw.opts.Filter != nil && !jw.opts.Filter(path, key, value, nodeValueType) {
+		jw.skip = level
+		return
+	}
+	var b []byte
+	if len(jw.open) > 0 {
+		if jw.open[len(jw.open)-1] > 0 {
+			b = append(b, ',')
+		}
+		jw.open[len(jw.open)-1]++
+		b = jw.newline(b, len(jw.open))
+		if k, ok := key.(string); ok {
+			b = jw.appendString(b, k)
+			b = append(b, ':')
+			if jw.opts.Indent != "" {
+				b = append(b, ' ')
+			}
+		}
+	}
+	switch nodeValueType {
+	case Array:
+		b = append(b, '[')
+		jw.open = append(jw.open, 0)
+	case Map:
+		b = append(b, '{')
+		jw.open = append(jw.open, 0)
+	case String:
+		b = jw.appendString(b, value.(string))
+	default:
+		v, err := json.Marshal(value)
+		if err != nil {
+			jw.err = fmt.Errorf("jsonwalk: |%v|: %w", path.Path(), err)
+			return
+		}
+		b = append(b, v...)
+	}
+	if len(jw.open) == 0 {
+		b = append(b, '\n') // a root leaf
+	}
+	jw.write(b)
+}
+
+func (jw *jsonWriter) L(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType) {
+	level := path.Level()
+	if jw.err != nil || jw.skip >= 0 && level >= jw.skip || len(jw.open) == 0 {
+		if jw.skip == level {
+			jw.skip = -1
+		}
+		return
+	}
+	if len(jw.open) != level+1 {
+		return
+	}
+	var b []byte
+	if jw.open[len(jw.open)-1] > 0 {
+		b = jw.newline(b, len(jw.open)-1)
+	}
+	jw.open = jw.open[:len(jw.open)-1]
+	if nodeValueType == Array {
+		b = append(b, ']')
+	} else {
+		b = append(b, '}')
+	}
+	if len(jw.open) == 0 {
+		b = append(b, '\n')
+	}
+	jw.write(b)
+}
+
+func (jw *jsonWriter) newline(b []byte, level int) []byte {
+	if jw.opts.Indent == "" {
+		return b
+	}
+	b = append(b, '\n')
+	return append(b, strings.Repeat(jw.opts.Indent, level)...)
+}
+
+func (jw *jsonWriter) appendString(b []byte, s string) []byte {
+	if !jw.opts.EscapeHTML {
+		return appendCanonicalString(b, s)
+	}
+	v, _ := json.Marshal(s) // never fails for a string
+	return append(b, v...)
+}
+
+func (jw *jsonWriter) write(b []byte) {
+	if _, err := jw.w.Write(b); err != nil {
+		jw.err = err
+	}
+}
+
+// WriteJSON writes the tree at root to w as JSON with NewJSONWriter, walking it with WalkSorted
+// if opts.SortKeys is set.
+func WriteJSON(w io.Writer, root *interface{}, opts *JSONWriterOptions) error {
+	jw := NewJSONWriter(w, opts)
+	if jw.opts.SortKeys {
+		WalkSorted(root, jw)
+	} else {
+		Walk(root, jw)
+	}
+	return jw.Err()
+}
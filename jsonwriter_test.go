@@ -0,0 +1,104 @@
+package jsonwalk_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/zzwx/jsonwalk"
+)
+
+func ExampleNewJSONWriter() {
+	var f interface{}
+	err := json.Unmarshal([]byte(actorsJSON), &f)
+	if err != nil {
+		return
+	}
+	jw := jsonwalk.NewJSONWriter(os.Stdout, &jsonwalk.JSONWriterOptions{
+		Indent: "  ",
+		Filter: func(path jsonwalk.WalkPath, key interface{}, value interface{}, nodeValueType jsonwalk.NodeValueType) bool {
+			k, isKey := key.(string)
+			return !isKey || k == "Actors" || k == "name"
+		},
+	})
+	jsonwalk.WalkSorted(&f, jw)
+	if jw.Err() != nil {
+		return
+	}
+	// Output:
+	// {
+	//   "Actors": [
+	//     {
+	//       "name": "Tom Cruise"
+	//     },
+	//     {
+	//       "name": "Robert Downey Jr."
+	//     }
+	//   ]
+	// }
+}
+
+func TestJSONWriter(t *testing.T) {
+	src := `{"a": [1, {"b": null, "c": "<&>"}, [], {}], "d": true, "e": 1e30}`
+	var f interface{}
+	err := json.Unmarshal([]byte(src), &f)
+	if err != nil {
+		t.Errorf("error umarshalling json: %v", err)
+		return
+	}
+	var b bytes.Buffer
+	if err := jsonwalk.WriteJSON(&b, &f, &jsonwalk.JSONWriterOptions{SortKeys: true}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	expected := `{"a":[1,{"b":null,"c":"<&>"},[],{}],"d":true,"e":1e+30}` + "\n"
+	if b.String() != expected {
+		t.Errorf("expected %v, got %v", expected, b.String())
+	}
+
+	// The output matches json.MarshalIndent, which sorts the keys and escapes HTML.
+	b.Reset()
+	_ = jsonwalk.WriteJSON(&b, &f, &jsonwalk.JSONWriterOptions{SortKeys: true, Indent: "\t", EscapeHTML: true})
+	indented, _ := json.MarshalIndent(f, "", "\t")
+	if b.String() != string(indented)+"\n" {
+		t.Errorf("expected %s, got %v", indented, b.String())
+	}
+
+	// Dropping nodes, limiting and writing several documents with the same writer.
+	b.Reset()
+	jw := jsonwalk.NewJSONWriter(&b, &jsonwalk.JSONWriterOptions{
+		Filter: func(path jsonwalk.WalkPath, key interface{}, value interface{}, nodeValueType jsonwalk.NodeValueType) bool {
+			return key != "a"
+		},
+	})
+	jsonwalk.WalkSorted(&f, jw)
+	jsonwalk.WalkSorted(&f, jsonwalk.Limit(3, jw))
+	var s interface{} = "x"
+	jsonwalk.Walk(&s, jw)
+	// Limit counts the nodes before the writer drops "a", so only the root is written.
+	expected = `{"d":true,"e":1e+30}` + "\n" + `{}` + "\n" + `"x"` + "\n"
+	if b.String() != expected {
+		t.Errorf("expected %v, got %v", expected, b.String())
+	}
+
+	// Nodes under the containers an outer Filter rejects are not written.
+	var g interface{}
+	if err := json.Unmarshal([]byte(`{"a":[1,{"s":"x"}],"b":{"c":"d"}}`), &g); err != nil {
+		t.Errorf("error umarshalling json: %v", err)
+		return
+	}
+	b.Reset()
+	jw = jsonwalk.NewJSONWriter(&b, nil)
+	jsonwalk.WalkSorted(&g, jsonwalk.OnlyTypes(jw, jsonwalk.String, jsonwalk.Map))
+	expected = `{"b":{"c":"d"}}` + "\n"
+	if b.String() != expected || jw.Err() != nil {
+		t.Errorf("expected %v, got %v (%v)", expected, b.String(), jw.Err())
+	}
+
+	var nan interface{} = []interface{}{math.NaN()}
+	if err := jsonwalk.WriteJSON(&b, &nan, nil); err == nil || !strings.Contains(err.Error(), "[0]") {
+		t.Errorf("expected an error for NaN, got %v", err)
+	}
+}
@@ -0,0 +1,223 @@
+package jsonwalk
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// MalformedLines selects what WalkLinesWith does with the lines that aren't valid JSON.
+type MalformedLines int
+
+const (
+	MalformedStop    MalformedLines = iota // Stop walking and return a *LineError.
+	MalformedSkip                          // Skip the line silently.
+	MalformedCollect                       // Skip the line and return all of them in LineErrors once the input is over.
+)
+
+// LineError is a line that WalkLines couldn't decode.
+type LineError struct {
+	Line int // starting with 1
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("jsonwalk: line %d: %v", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// LineErrors is returned by WalkLinesWith with MalformedCollect when one or more lines could not be decoded.
+type LineErrors []*LineError
+
+func (e LineErrors) Error() string {
+	s := make([]string, len(e))
+	for i, err := range e {
+		s[i] = err.Error()
+	}
+	return strings.Join(s, "\n")
+}
+
+// LinesOptions configures WalkLinesWith. A nil *LinesOptions is the same as the zero value.
+type LinesOptions struct {
+	Malformed MalformedLines
+	// Workers is the number of goroutines decoding the lines ahead of the walk. 0 or 1 decodes them
+	// one by one as they are walked. The records are walked in the order of the lines either way,
+	// with the callback always called from the goroutine that called WalkLinesWith.
+	Workers int
+	// Sorted walks the records the way WalkSorted does.
+	Sorted bool
+}
+
+// WalkLines walks every line of r, which is newline-delimited JSON (NDJSON, JSON Lines), as a separate record,
+// stopping at the first malformed line. See WalkLinesWith.
+func WalkLines(r io.Reader, callback WalkCallback) error {
+	return WalkLinesWith(r, callback, nil)
+}
+
+// WalkLinesWith walks every line of r, which is newline-delimited JSON (NDJSON, JSON Lines), as a separate record.
+// The paths start with the line of the record, starting with 1: the root of the record on line 12 has the path "[12]"
+// and its "msg" member has the path "[12].msg". The levels start with 0 at the root of every record, as they do for Walk,
+// so callbacks producing a document per walk, such as NewJSONWriter or NewYAMLWriter, produce one for every record.
+// LineNumber returns the line a path belongs to:
+//
+//	err := jsonwalk.WalkLinesWith(f, jsonwalk.Callback(func(path jsonwalk.WalkPath, key interface{}, value interface{}, nodeValueType jsonwalk.NodeValueType) {
+//		line, _ := jsonwalk.LineNumber(path)
+//		...
+//	}), &jsonwalk.LinesOptions{Malformed: jsonwalk.MalformedCollect, Workers: 4})
+//
+// Blank lines are skipped. Malformed lines are handled according to opts.Malformed.
+// Errors reading r stop the walk and are returned as is.
+func WalkLinesWith(r io.Reader, callback WalkCallback, opts *LinesOptions) error {
+	var o LinesOptions
+	if opts != nil {
+		o = *opts
+	}
+	lr := &lineReader{r: bufio.NewReader(r)}
+	var errs LineErrors
+	walk := func(rec *lineRecord) error {
+		switch {
+		case rec.err != nil && o.Malformed == MalformedStop:
+			return &LineError{Line: rec.line, Err: rec.err}
+		case rec.err != nil && o.Malformed == MalformedCollect:
+			errs = append(errs, &LineError{Line: rec.line, Err: rec.err})
+		case rec.err == nil && !rec.blank:
+			w(linePath(rec.line), nil, &rec.value, callback, o.Sorted)
+		}
+		return nil
+	}
+
+	if o.Workers <= 1 {
+		for {
+			rec, err := lr.next()
+			if err != nil || rec == nil {
+				return linesResult(err, errs)
+			}
+			rec.decode()
+			if err := walk(rec); err != nil {
+				return err
+			}
+		}
+	}
+
+	quit := make(chan struct{})
+	defer close(quit)
+	ordered := make(chan *lineRecord, 2*o.Workers)
+	jobs := make(chan *lineRecord, 2*o.Workers)
+	go func() {
+		defer close(ordered)
+		defer close(jobs)
+		for {
+			rec, err := lr.next()
+			if err != nil {
+				rec = &lineRecord{readErr: err, done: make(chan struct{})}
+				close(rec.done)
+			} else if rec == nil {
+				return
+			}
+			select {
+			case ordered <- rec:
+			case <-quit:
+				return
+			}
+			if err != nil {
+				return
+			}
+			select {
+			case jobs <- rec:
+			case <-quit:
+				return
+			}
+		}
+	}()
+	for i := 0; i < o.Workers; i++ {
+		go func() {
+			for rec := range jobs {
+				rec.decode()
+				close(rec.done)
+			}
+		}()
+	}
+	for rec := range ordered {
+		<-rec.done
+		if rec.readErr != nil {
+			return linesResult(rec.readErr, errs)
+		}
+		if err := walk(rec); err != nil {
+			return err
+		}
+	}
+	return linesResult(nil, errs)
+}
+
+// LineNumber returns the line, starting with 1, of the record walked by WalkLines that path p belongs to.
+// It returns false for the paths of other walks.
+func LineNumber(p WalkPath) (int, bool) {
+	if wp, ok := p.(walkPath); ok && wp.line > 0 {
+		return wp.line, true
+	}
+	return 0, false
+}
+
+// linePath returns the root path of the record on line n, which renders as "[n]" but has the level of 0.
+func linePath(n int) walkPath {
+	p := newWalkPath().ArrayEl(n)
+	p.level = 0
+	p.line = n
+	return p
+}
+
+// lineReader splits the input into lines of any length.
+type lineReader struct {
+	r    *bufio.Reader
+	line int
+}
+
+// lineRecord is a line with its decoded value.
+type lineRecord struct {
+	line    int
+	data    []byte
+	value   interface{}
+	blank   bool
+	err     error         // decoding error
+	readErr error         // error reading the input, which ends it
+	done    chan struct{} // closed once decoded
+}
+
+// next returns the next line, or nil at the end of the input.
+func (lr *lineReader) next() (*lineRecord, error) {
+	data, err := lr.r.ReadBytes('\n')
+	if err != nil && (err != io.EOF || len(data) == 0) {
+		if err == io.EOF {
+			err = nil
+		}
+		return nil, err
+	}
+	lr.line++
+	return &lineRecord{line: lr.line, data: data, done: make(chan struct{})}, nil
+}
+
+// linesResult returns the error that ends the walk.
+func linesResult(readErr error, errs LineErrors) error {
+	switch {
+	case readErr != nil:
+		return readErr
+	case len(errs) > 0:
+		return errs
+	}
+	return nil
+}
+
+func (rec *lineRecord) decode() {
+	if len(bytes.TrimSpace(rec.data)) == 0 {
+		rec.blank = true
+		return
+	}
+	rec.err = json.Unmarshal(rec.data, &rec.value)
+	rec.data = nil
+}
@@ -0,0 +1,111 @@
+package jsonwalk_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/zzwx/jsonwalk"
+)
+
+func ExampleWalkLinesWith() {
+	logs := `{"level": "info", "msg": "started"}
+{"level": "error", "msg": "disk full"
+
+{"level": "error", "msg": "retrying"}
+`
+	err := jsonwalk.WalkLinesWith(strings.NewReader(logs), jsonwalk.Callback(func(path jsonwalk.WalkPath, key interface{}, value interface{}, nodeValueType jsonwalk.NodeValueType) {
+		if key == "level" && value == "error" {
+			line, _ := jsonwalk.LineNumber(path)
+			fmt.Printf("line %v: %v\n", line, path.Path())
+		}
+	}), &jsonwalk.LinesOptions{Malformed: jsonwalk.MalformedCollect})
+	fmt.Println(err)
+	// Output:
+	// line 4: [4].level
+	// jsonwalk: line 2: unexpected end of JSON input
+}
+
+func TestWalkLines(t *testing.T) {
+	var b strings.Builder
+	for i := 1; i <= 1000; i++ {
+		if i%100 == 0 {
+			b.WriteString("{bad}\n")
+		} else {
+			_, _ = fmt.Fprintf(&b, "{\"n\": %d}\n", i)
+		}
+	}
+	input := strings.TrimSuffix(b.String(), "\n") // no newline after the last line
+
+	for _, workers := range []int{0, 1, 4} {
+		for _, malformed := range []jsonwalk.MalformedLines{jsonwalk.MalformedStop, jsonwalk.MalformedSkip, jsonwalk.MalformedCollect} {
+			var lines []int
+			err := jsonwalk.WalkLinesWith(strings.NewReader(input), jsonwalk.Callback(func(path jsonwalk.WalkPath, key interface{}, value interface{}, nodeValueType jsonwalk.NodeValueType) {
+				line, ok := jsonwalk.LineNumber(path)
+				if !ok || key == "n" && value != float64(line) {
+					t.Errorf("unexpected %v on line %v", value, line)
+				}
+				if prefix := fmt.Sprintf("[%d]", line); !strings.HasPrefix(path.Path(), prefix) {
+					t.Errorf("expected path %v to start with %v", path.Path(), prefix)
+				}
+				if path.Level() == 0 {
+					lines = append(lines, line)
+				}
+			}), &jsonwalk.LinesOptions{Malformed: malformed, Workers: workers})
+
+			expected := 990
+			var lineErrs jsonwalk.LineErrors
+			var lineErr *jsonwalk.LineError
+			switch {
+			case malformed == jsonwalk.MalformedStop:
+				expected = 99
+				if !errors.As(err, &lineErr) || lineErr.Line != 100 {
+					t.Errorf("workers %v: expected an error on line 100, got %v", workers, err)
+				}
+			case malformed == jsonwalk.MalformedCollect:
+				if !errors.As(err, &lineErrs) || len(lineErrs) != 10 || lineErrs[9].Line != 1000 {
+					t.Errorf("workers %v: expected 10 line errors, got %v", workers, err)
+				}
+			case err != nil:
+				t.Errorf("workers %v: unexpected error %v", workers, err)
+			}
+			if len(lines) != expected {
+				t.Errorf("workers %v, malformed %v: expected %v records, got %v", workers, malformed, expected, len(lines))
+			}
+			for i := 1; i < len(lines); i++ {
+				if lines[i] <= lines[i-1] {
+					t.Errorf("workers %v: line %v walked after line %v", workers, lines[i], lines[i-1])
+					break
+				}
+			}
+		}
+	}
+
+	// Writers tracking the levels produce a document for every record.
+	var b2 strings.Builder
+	yw := jsonwalk.NewYAMLWriter(&b2)
+	if err := jsonwalk.WalkLinesWith(strings.NewReader("{\"a\":1}\n{\"b\":[2]}\n"), yw, &jsonwalk.LinesOptions{Sorted: true}); err != nil || yw.Err() != nil {
+		t.Errorf("unexpected error: %v, %v", err, yw.Err())
+	}
+	expected := "a: 1\n---\nb:\n  - 2\n"
+	if b2.String() != expected {
+		t.Errorf("expected:\n%v\ngot:\n%v", expected, b2.String())
+	}
+
+	var f interface{} = map[string]interface{}{"a": 1.0}
+	jsonwalk.Walk(&f, jsonwalk.Callback(func(path jsonwalk.WalkPath, key interface{}, value interface{}, nodeValueType jsonwalk.NodeValueType) {
+		if _, ok := jsonwalk.LineNumber(path); ok {
+			t.Errorf("unexpected line number for %v", path.Path())
+		}
+	}))
+
+	errRead := errors.New("read failed")
+	for _, workers := range []int{0, 4} {
+		err := jsonwalk.WalkLinesWith(iotest.ErrReader(errRead), nil, &jsonwalk.LinesOptions{Workers: workers})
+		if !errors.Is(err, errRead) {
+			t.Errorf("workers %v: expected the read error, got %v", workers, err)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package jsonwalk
+
+import (
+	"fmt"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// ArrayMerge selects how Merge combines two arrays found at the same path.
+type ArrayMerge int
+
+const (
+	ArrayReplace    ArrayMerge = iota // The source array replaces the destination one.
+	ArrayAppend                       // Source elements are appended to the destination array.
+	ArrayDedupe                       // Source elements are appended unless an equal element is already there.
+	ArrayMergeByKey                   // Map elements with the same value under MergeRule.Key are merged, others are appended.
+)
+
+// ScalarMerge selects how Merge combines two different leaf values found at the same path.
+type ScalarMerge int
+
+const (
+	PreferRight    ScalarMerge = iota // The source value wins.
+	PreferLeft                        // The destination value is kept.
+	ScalarConflict                    // Different values are a conflict.
+)
+
+// MergeRule is a strategy for merging the nodes which paths match the Pattern.
+type MergeRule struct {
+	// Pattern is a path in the form returned by WalkPath.Path(), where "*" matches any Map key,
+	// "[*]" matches any array index and "**" matches any number of path elements,
+	// such as "spec.containers" or "**.env". Backslash escapes ".", "[", "]", "*" and "\" inside keys.
+	Pattern string
+	Arrays  ArrayMerge
+	// Key identifying Map elements of arrays for ArrayMergeByKey, such as "name".
+	Key     string
+	Scalars ScalarMerge
+}
+
+// MergeStrategy configures Merge. A nil *MergeStrategy merges Maps recursively, replaces arrays and prefers source leaves.
+type MergeStrategy struct {
+	// Rules are checked in order and the first one which Pattern matches the path of a node applies to it.
+	Rules []MergeRule
+	// Default applies to the nodes that don't match any of the Rules. Its Pattern is ignored.
+	Default MergeRule
+	// OnConflict is called with the destination path and both values when they can't be merged:
+	// when they are of different types, such as a Map and an Array, or when they are different leaves
+	// under the ScalarConflict rule. It returns the value to use instead, or an error to stop the merge.
+	// A nil OnConflict makes Merge stop at the first conflict with a *MergeConflictError.
+	OnConflict func(path WalkPath, dst, src interface{}) (interface{}, error)
+}
+
+// MergeConflictError is returned by Merge for a conflict when there's no MergeStrategy.OnConflict to resolve it.
+type MergeConflictError struct {
+	Path     WalkPath
+	Dst, Src interface{}
+}
+
+func (e *MergeConflictError) Error() string {
+	return fmt.Sprintf("jsonwalk: merge conflict at |%v|: %v (%v) and %v (%v)",
+		e.Path.Path(), compactJSON(e.Dst), typeHint(e.Dst), compactJSON(e.Src), typeHint(e.Src))
+}
+
+// Merge deeply merges the tree at src into the tree at dst.
+//
+// Maps are merged member by member. Arrays, as well as two different leaves, are combined as selected by
+// the first MergeRule of the strategy which pattern matches their path:
+//
+//	jsonwalk.Merge(&dst, &src, &jsonwalk.MergeStrategy{
+//		Rules: []jsonwalk.MergeRule{
+//			{Pattern: "Actors", Arrays: jsonwalk.ArrayMergeByKey, Key: "name"},
+//			{Pattern: "Actors[*].children", Arrays: jsonwalk.ArrayDedupe},
+//		},
+//	})
+//
+// A null on either side is treated as a leaf, so it's not a conflict with a value of any other type.
+//
+// The merge is atomic: dst is only updated if no error is returned. Values taken from src are copied,
+// so that the two trees don't share any nodes.
+func Merge(dst, src *interface{}, strategy *MergeStrategy) error {
+	m := merger{}
+	if strategy != nil {
+		m.s = *strategy
+	}
+	for _, r := range m.s.Rules {
+		pattern, err := parsePath(r.Pattern, ".", true)
+		if err != nil {
+			return fmt.Errorf("jsonwalk: merge rule: %w", err)
+		}
+		m.patterns = append(m.patterns, pattern)
+	}
+	v, err := m.merge(newWalkPath(), deepCopy(*dst), *src)
+	if err != nil {
+		return err
+	}
+	*dst = v
+	return nil
+}
+
+type merger struct {
+	s        MergeStrategy
+	patterns [][]pathSeg // parsed patterns of s.Rules
+}
+
+func (m *merger) rule(path walkPath) MergeRule {
+	segs := path.segments()
+	for i, p := range m.patterns {
+		if matchSegs(p, segs) {
+			return m.s.Rules[i]
+		}
+	}
+	return m.s.Default
+}
+
+func (m *merger) conflict(path walkPath, dst, src interface{}) (interface{}, error) {
+	if m.s.OnConflict == nil {
+		return nil, &MergeConflictError{Path: path, Dst: dst, Src: src}
+	}
+	v, err := m.s.OnConflict(path, dst, src)
+	if err != nil {
+		return nil, err
+	}
+	return deepCopy(v), nil
+}
+
+func (m *merger) merge(path walkPath, dst, src interface{}) (interface{}, error) {
+	dt, st := t(dst), t(src)
+	switch {
+	case dt == Map && st == Map:
+		dm, sm := dst.(map[string]interface{}), src.(map[string]interface{})
+		keys := maps.Keys(sm)
+		slices.Sort(keys)
+		for _, k := range keys {
+			dv, ok := dm[k]
+			if !ok {
+				dm[k] = deepCopy(sm[k])
+				continue
+			}
+			v, err := m.merge(path.MapEl(k), dv, sm[k])
+			if err != nil {
+				return nil, err
+			}
+			dm[k] = v
+		}
+		return dm, nil
+	case dt == Array && st == Array:
+		return m.mergeArrays(path, dst.([]interface{}), src.([]interface{}))
+	case dt != st && dt != Nil && st != Nil:
+		return m.conflict(path, dst, src)
+	case deepEqual(dst, src):
+		return dst, nil
+	}
+	switch m.rule(path).Scalars {
+	case PreferLeft:
+		return dst, nil
+	case ScalarConflict:
+		return m.conflict(path, dst, src)
+	default:
+		return deepCopy(src), nil
+	}
+}
+
+func (m *merger) mergeArrays(path walkPath, dst, src []interface{}) (interface{}, error) {
+	r := m.rule(path)
+	switch r.Arrays {
+	case ArrayAppend:
+		for _, v := range src {
+			dst = append(dst, deepCopy(v))
+		}
+	case ArrayDedupe:
+		for _, v := range src {
+			if slices.IndexFunc(dst, func(el interface{}) bool { return deepEqual(el, v) }) < 0 {
+				dst = append(dst, deepCopy(v))
+			}
+		}
+	case ArrayMergeByKey:
+		for _, v := range src {
+			id, ok := keyIdentity(v, r.Key)
+			i := -1
+			if ok {
+				i = slices.IndexFunc(dst, func(el interface{}) bool {
+					elID, ok := keyIdentity(el, r.Key)
+					return ok && elID == id
+				})
+			}
+			if i < 0 {
+				dst = append(dst, deepCopy(v))
+				continue
+			}
+			merged, err := m.merge(path.ArrayEl(i), dst[i], v)
+			if err != nil {
+				return nil, err
+			}
+			dst[i] = merged
+		}
+	default:
+		return deepCopy(src), nil
+	}
+	return dst, nil
+}
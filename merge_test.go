@@ -0,0 +1,93 @@
+package jsonwalk_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/zzwx/jsonwalk"
+)
+
+func ExampleMerge() {
+	var base, overlay interface{}
+	err := json.Unmarshal([]byte(`{"Actors": [{"name": "Tom Cruise", "age": 56, "children": ["Suri"]}], "tags": ["a"]}`), &base)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal([]byte(`{"Actors": [{"name": "Tom Cruise", "age": 57, "children": ["Suri", "Connor"]}, {"name": "Robert Downey Jr."}], "tags": ["b"]}`), &overlay)
+	if err != nil {
+		return
+	}
+	err = jsonwalk.Merge(&base, &overlay, &jsonwalk.MergeStrategy{
+		Rules: []jsonwalk.MergeRule{
+			{Pattern: "Actors", Arrays: jsonwalk.ArrayMergeByKey, Key: "name"},
+			{Pattern: "Actors[*].children", Arrays: jsonwalk.ArrayDedupe},
+			{Pattern: "Actors[*].age", Scalars: jsonwalk.PreferLeft},
+			{Pattern: "tags", Arrays: jsonwalk.ArrayAppend},
+		},
+	})
+	if err != nil {
+		return
+	}
+	b, _ := json.Marshal(base)
+	fmt.Println(string(b))
+	// Output:
+	// {"Actors":[{"age":56,"children":["Suri","Connor"],"name":"Tom Cruise"},{"name":"Robert Downey Jr."}],"tags":["a","b"]}
+}
+
+func TestMerge(t *testing.T) {
+	var dst, src interface{}
+	_ = json.Unmarshal([]byte(`{"a": {"b": 1, "c": [1, 2]}, "d": "x", "e": null, "f": [1]}`), &dst)
+	_ = json.Unmarshal([]byte(`{"a": {"b": 2, "c": [2, 3], "g": true}, "d": {"y": 1}, "e": 5, "f": {"z": 1}}`), &src)
+
+	// The default strategy stops at the first conflict and leaves dst untouched.
+	err := jsonwalk.Merge(&dst, &src, nil)
+	var cerr *jsonwalk.MergeConflictError
+	if !errors.As(err, &cerr) || cerr.Path.Path() != "d" {
+		t.Errorf("expected a conflict at d, got %v", err)
+	}
+	if b, _ := json.Marshal(dst); string(b) != `{"a":{"b":1,"c":[1,2]},"d":"x","e":null,"f":[1]}` {
+		t.Errorf("dst should be untouched, got %s", b)
+	}
+
+	var conflicts []string
+	err = jsonwalk.Merge(&dst, &src, &jsonwalk.MergeStrategy{
+		Rules:   []jsonwalk.MergeRule{{Pattern: "**.c", Arrays: jsonwalk.ArrayDedupe}},
+		Default: jsonwalk.MergeRule{Scalars: jsonwalk.ScalarConflict},
+		OnConflict: func(path jsonwalk.WalkPath, dst, src interface{}) (interface{}, error) {
+			conflicts = append(conflicts, path.Path())
+			return src, nil
+		},
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if fmt.Sprint(conflicts) != "[a.b d e f]" {
+		t.Errorf("unexpected conflicts: %v", conflicts)
+	}
+	if b, _ := json.Marshal(dst); string(b) != `{"a":{"b":2,"c":[1,2,3],"g":true},"d":{"y":1},"e":5,"f":{"z":1}}` {
+		t.Errorf("unexpected merge result: %s", b)
+	}
+
+	stop := errors.New("stop")
+	err = jsonwalk.Merge(&dst, &src, &jsonwalk.MergeStrategy{
+		Default: jsonwalk.MergeRule{Scalars: jsonwalk.ScalarConflict},
+		OnConflict: func(path jsonwalk.WalkPath, dst, src interface{}) (interface{}, error) {
+			return nil, stop
+		},
+	})
+	if err != nil {
+		t.Errorf("identical trees should not conflict, got %v", err)
+	}
+	src.(map[string]interface{})["e"] = 6.0
+	err = jsonwalk.Merge(&dst, &src, &jsonwalk.MergeStrategy{
+		Default: jsonwalk.MergeRule{Scalars: jsonwalk.ScalarConflict},
+		OnConflict: func(path jsonwalk.WalkPath, dst, src interface{}) (interface{}, error) {
+			return nil, stop
+		},
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("expected the callback error, got %v", err)
+	}
+}
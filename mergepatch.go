@@ -0,0 +1,104 @@
+package jsonwalk
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNullMember is returned by CreateMergePatch when the target document has a Map member set to null,
+// which a JSON Merge Patch can't express since null removes the member instead.
+var ErrNullMember = errors.New("null member can't be expressed in a merge patch")
+
+// MergePatch applies a JSON Merge Patch (RFC 7386) to the tree at target:
+// Map members of patch that are null remove the corresponding members of target,
+// other Map members are merged recursively, and any other patch value, including an Array, replaces the target value.
+//
+//	target: {"a": "b", "c": {"d": "e", "f": "g"}}
+//	patch:  {"a": "z", "c": {"f": null}}
+//	result: {"a": "z", "c": {"d": "e"}}
+//
+// Maps of target are modified in place. Values taken from patch are copied, so that the two trees don't share any nodes.
+func MergePatch(target, patch *interface{}) {
+	*target = mergePatch(*target, *patch)
+}
+
+func mergePatch(target, patch interface{}) interface{} {
+	pm, ok := patch.(map[string]interface{})
+	if !ok {
+		t(patch) // Only the known node types are accepted
+		return deepCopy(patch)
+	}
+	tm, ok := target.(map[string]interface{})
+	if !ok {
+		tm = make(map[string]interface{}, len(pm))
+	}
+	for k, v := range pm {
+		if v == nil {
+			delete(tm, k)
+			continue
+		}
+		tm[k] = mergePatch(tm[k], v)
+	}
+	return tm
+}
+
+// CreateMergePatch returns a JSON Merge Patch (RFC 7386) that turns tree a into tree b when applied with MergePatch.
+// Unchanged Map members are left out of the patch and removed members are set to null.
+//
+// If b has a Map member that is null and isn't null in a already, ErrNullMember is returned, wrapped with the path of the member.
+func CreateMergePatch(a, b *interface{}) (interface{}, error) {
+	return createMergePatch(newWalkPath(), *a, *b)
+}
+
+func createMergePatch(path walkPath, a, b interface{}) (interface{}, error) {
+	am, aok := a.(map[string]interface{})
+	bm, bok := b.(map[string]interface{})
+	if !aok || !bok {
+		if err := checkNullMembers(path, b); err != nil {
+			return nil, err
+		}
+		return deepCopy(b), nil
+	}
+	patch := map[string]interface{}{}
+	for k := range am {
+		if _, ok := bm[k]; !ok {
+			patch[k] = nil
+		}
+	}
+	for k, bv := range bm {
+		av, ok := am[k]
+		if ok && deepEqual(av, bv) {
+			continue
+		}
+		if bv == nil {
+			return nil, fmt.Errorf("jsonwalk: %v: %w", path.MapEl(k).Path(), ErrNullMember)
+		}
+		if !ok {
+			av = nil
+		}
+		v, err := createMergePatch(path.MapEl(k), av, bv)
+		if err != nil {
+			return nil, err
+		}
+		patch[k] = v
+	}
+	return patch, nil
+}
+
+// checkNullMembers returns an error if any Map inside v has a member set to null.
+func checkNullMembers(path walkPath, v interface{}) error {
+	switch vt := v.(type) {
+	case map[string]interface{}:
+		for k, el := range vt {
+			if el == nil {
+				return fmt.Errorf("jsonwalk: %v: %w", path.MapEl(k).Path(), ErrNullMember)
+			}
+			if err := checkNullMembers(path.MapEl(k), el); err != nil {
+				return err
+			}
+		}
+	default:
+		t(v) // Only the known node types are accepted
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package jsonwalk_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/zzwx/jsonwalk"
+)
+
+func ExampleMergePatch() {
+	var defaults, overrides interface{}
+	err := json.Unmarshal([]byte(`{"title": "Goodbye!", "author": {"givenName": "John", "familyName": "Doe"}, "tags": ["example", "sample"]}`), &defaults)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal([]byte(`{"title": "Hello!", "phoneNumber": "+01-123-456-7890", "author": {"familyName": null}, "tags": ["example"]}`), &overrides)
+	if err != nil {
+		return
+	}
+	jsonwalk.MergePatch(&defaults, &overrides)
+	b, _ := json.Marshal(defaults)
+	fmt.Println(string(b))
+	// Output:
+	// {"author":{"givenName":"John"},"phoneNumber":"+01-123-456-7890","tags":["example"],"title":"Hello!"}
+}
+
+func TestMergePatch(t *testing.T) {
+	// Examples from RFC 7386, Appendix A.
+	for _, c := range [][3]string{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	} {
+		var target, patch, expected interface{}
+		_ = json.Unmarshal([]byte(c[0]), &target)
+		_ = json.Unmarshal([]byte(c[1]), &patch)
+		_ = json.Unmarshal([]byte(c[2]), &expected)
+		jsonwalk.MergePatch(&target, &patch)
+		if !reflect.DeepEqual(target, expected) {
+			t.Errorf("%v + %v: expected %v, got %v", c[0], c[1], c[2], target)
+		}
+	}
+}
+
+func TestCreateMergePatch(t *testing.T) {
+	for _, c := range [][2]string{
+		{actorsJSON, `{"Actors": [{"name": "Tom Cruise"}], "count": 1}`},
+		{`{"a": {"b": 1, "c": [1, null]}, "d": "e"}`, `{"a": {"b": 2, "c": [1, null]}, "f": {"g": true}}`},
+		{`{"a": null}`, `{"a": null, "b": 1}`},
+		{`[1]`, `{"a": {}}`},
+		{`{"a": 1}`, `"a"`},
+	} {
+		var a, b interface{}
+		_ = json.Unmarshal([]byte(c[0]), &a)
+		_ = json.Unmarshal([]byte(c[1]), &b)
+		patch, err := jsonwalk.CreateMergePatch(&a, &b)
+		if err != nil {
+			t.Errorf("%v -> %v: %v", c[0], c[1], err)
+			continue
+		}
+		jsonwalk.MergePatch(&a, &patch)
+		if !reflect.DeepEqual(a, b) {
+			t.Errorf("%v -> %v: got %v", c[0], c[1], a)
+		}
+	}
+
+	var a, b interface{}
+	_ = json.Unmarshal([]byte(`{"a": {"b": 1}}`), &a)
+	_ = json.Unmarshal([]byte(`{"a": {"b": null}}`), &b)
+	_, err := jsonwalk.CreateMergePatch(&a, &b)
+	if !errors.Is(err, jsonwalk.ErrNullMember) {
+		t.Errorf("expected ErrNullMember, got %v", err)
+	}
+}
@@ -0,0 +1,209 @@
+package jsonwalk
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"math"
+	"time"
+)
+
+// DecodeMsgPack decodes all the MessagePack objects read from r, one after another,
+// into the types json.Unmarshal produces, so that they can be walked as any other document:
+//
+//   - integers become float64, the way encoding/json decodes them,
+//     so integers beyond ±2^53, such as nanosecond timestamps, lose their lowest digits;
+//   - floats become float64, except for NaN and infinities, which can't be written as JSON
+//     and become the strings "NaN", "+Inf" and "-Inf";
+//   - bin objects become base64 strings (standard encoding with padding);
+//   - map keys that aren't strings are formatted as strings: 1 as "1", true as "true",
+//     and collections as compact JSON. Keys that end up the same, such as 1 and "1", are errors;
+//   - timestamps (extension type -1) become RFC 3339 strings in UTC,
+//     other extensions become Maps of their "type" number and base64 "data".
+func DecodeMsgPack(r io.Reader) ([]interface{}, error) {
+	d := msgpackDecoder{newBinaryReader(r, "msgpack")}
+	var objs []interface{}
+	for {
+		more, err := d.more()
+		if err != nil {
+			return nil, d.eof(err)
+		}
+		if !more {
+			return objs, nil
+		}
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, v)
+	}
+}
+
+// WalkMsgPack decodes the MessagePack objects read from r with DecodeMsgPack and walks them one after another with Walk.
+func WalkMsgPack(r io.Reader, callback WalkCallback) error {
+	objs, err := DecodeMsgPack(r)
+	if err != nil {
+		return err
+	}
+	for i := range objs {
+		Walk(&objs[i], callback)
+	}
+	return nil
+}
+
+type msgpackDecoder struct {
+	*binaryReader
+}
+
+func (d msgpackDecoder) value() (interface{}, error) {
+	c, err := d.byte()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case c <= 0x7f:
+		return float64(c), nil
+	case c <= 0x8f:
+		return d.mapping(uint64(c & 0x0f))
+	case c <= 0x9f:
+		return d.array(uint64(c & 0x0f))
+	case c <= 0xbf:
+		return d.str(uint64(c & 0x1f))
+	case c >= 0xe0:
+		return float64(int8(c)), nil
+	}
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.uint(1 << (c - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.bytes(n)
+		if err != nil {
+			return nil, err
+		}
+		return base64.StdEncoding.EncodeToString(b), nil
+	case 0xc7, 0xc8, 0xc9:
+		n, err := d.uint(1 << (c - 0xc7))
+		if err != nil {
+			return nil, err
+		}
+		return d.ext(n)
+	case 0xca:
+		u, err := d.uint(4)
+		return binaryFloat(float64(math.Float32frombits(uint32(u)))), err
+	case 0xcb:
+		u, err := d.uint(8)
+		return binaryFloat(math.Float64frombits(u)), err
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		u, err := d.uint(1 << (c - 0xcc))
+		return float64(u), err
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (c - 0xd0)
+		u, err := d.uint(size)
+		// Sign-extend the size*8 bits.
+		shift := 64 - 8*size
+		return float64(int64(u<<shift) >> shift), err
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return d.ext(1 << (c - 0xd4))
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.uint(1 << (c - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return d.str(n)
+	case 0xdc, 0xdd:
+		n, err := d.uint(2 << (c - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.array(n)
+	case 0xde, 0xdf:
+		n, err := d.uint(2 << (c - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.mapping(n)
+	}
+	return nil, d.errorf("invalid format 0x%02x", c)
+}
+
+func (d msgpackDecoder) str(n uint64) (interface{}, error) {
+	b, err := d.bytes(n)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (d msgpackDecoder) array(n uint64) (interface{}, error) {
+	if err := d.enter(); err != nil {
+		return nil, err
+	}
+	defer d.leave()
+	a := []interface{}{}
+	for i := uint64(0); i < n; i++ {
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, v)
+	}
+	return a, nil
+}
+
+func (d msgpackDecoder) mapping(n uint64) (interface{}, error) {
+	if err := d.enter(); err != nil {
+		return nil, err
+	}
+	defer d.leave()
+	m := map[string]interface{}{}
+	for i := uint64(0); i < n; i++ {
+		k, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		if err := d.put(m, k, v); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// ext decodes an extension with n bytes of data.
+func (d msgpackDecoder) ext(n uint64) (interface{}, error) {
+	typ, err := d.byte()
+	if err != nil {
+		return nil, err
+	}
+	b, err := d.bytes(n)
+	if err != nil {
+		return nil, err
+	}
+	if int8(typ) != -1 {
+		return map[string]interface{}{"type": float64(int8(typ)), "data": base64.StdEncoding.EncodeToString(b)}, nil
+	}
+	var sec, nsec int64
+	switch n {
+	case 4:
+		sec = int64(binary.BigEndian.Uint32(b))
+	case 8:
+		u := binary.BigEndian.Uint64(b)
+		sec, nsec = int64(u&(1<<34-1)), int64(u>>34)
+	case 12:
+		sec, nsec = int64(binary.BigEndian.Uint64(b[4:])), int64(binary.BigEndian.Uint32(b))
+	default:
+		return nil, d.errorf("invalid timestamp length %d", n)
+	}
+	return time.Unix(sec, nsec).UTC().Format(time.RFC3339Nano), nil
+}
@@ -0,0 +1,82 @@
+package jsonwalk_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/zzwx/jsonwalk"
+)
+
+func ExampleDecodeMsgPack() {
+	// {"a": [1, -1, bin 0102], 1: true, "f": 1.5}
+	data, _ := hex.DecodeString("83a16193" + "01ffc4020102" + "01c3" + "a166cb3ff8000000000000")
+	objs, err := jsonwalk.DecodeMsgPack(bytes.NewReader(data))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	jsonwalk.WalkSorted(&objs[0], jsonwalk.Callback(func(path jsonwalk.WalkPath, key interface{}, value interface{}, nodeValueType jsonwalk.NodeValueType) {
+		if nodeValueType != jsonwalk.Array && nodeValueType != jsonwalk.Map {
+			fmt.Printf("%v = %#v\n", path.Path(), value)
+		}
+	}))
+	// Output:
+	// 1 = true
+	// a[0] = 1
+	// a[1] = -1
+	// a[2] = "AQI="
+	// f = 1.5
+}
+
+func TestDecodeMsgPack(t *testing.T) {
+	for _, tc := range []struct {
+		hex      string
+		expected string
+	}{
+		{"d080", `-128`},
+		{"d1ff00", `-256`},
+		{"d3fffffffffffffffe", `-2`},
+		{"cd0100", `256`},
+		{"cf0000000100000000", `4294967296`},
+		{"ca3fc00000", `1.5`},
+		{"d9026869", `"hi"`},
+		{"dc0002c0c2", `[null,false]`},
+		{"de000192c3c3a0", `{"[true,true]":""}`},
+		{"d6ff00000000", `"1970-01-01T00:00:00Z"`},
+		{"d7ff0000000400000000", `"1970-01-01T00:00:00.000000001Z"`},
+		{"d405aa", `{"data":"qg==","type":5}`},
+		{"9001", `[] 1`},
+		{"cf0020000000000000", `9007199254740992`},
+		{"d3ffe0000000000000", `-9007199254740992`},
+		{"cf17978b7a9e300001", `1699980743111868400`}, // 1699980743111868417, rounded like encoding/json does
+		{"d38000000000000000", `-9223372036854776000`},
+		{"92ca7fc00000cbfff0000000000000", `["NaN","-Inf"]`},
+	} {
+		data, _ := hex.DecodeString(tc.hex)
+		objs, err := jsonwalk.DecodeMsgPack(bytes.NewReader(data))
+		if err != nil {
+			t.Errorf("error decoding %v: %v", tc.hex, err)
+			continue
+		}
+		var got []string
+		for _, v := range objs {
+			b, _ := json.Marshal(v)
+			got = append(got, string(b))
+		}
+		if strings.Join(got, " ") != tc.expected {
+			t.Errorf("%v: expected %v, got %v", tc.hex, tc.expected, strings.Join(got, " "))
+		}
+	}
+
+	for _, h := range []string{"c1", "da000561", "81a161", "d5ff0000", strings.Repeat("91", 1001) + "01",
+		"8201c3a131c2"} {
+		data, _ := hex.DecodeString(h)
+		if err := jsonwalk.WalkMsgPack(bytes.NewReader(data), nil); err == nil {
+			t.Errorf("expected an error for %.20v", h)
+		}
+	}
+}
@@ -0,0 +1,421 @@
+package jsonwalk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+var (
+	// ErrNotFound is wrapped by PatchError when a path or a from location of an operation doesn't exist.
+	ErrNotFound = errors.New("path not found")
+	// ErrTestFailed is wrapped by PatchError when a "test" operation finds a different value.
+	ErrTestFailed = errors.New("test failed")
+)
+
+// Operation is a single JSON Patch (RFC 6902) operation with JSON Pointer (RFC 6901) paths.
+// It can be marshalled to and unmarshalled from its JSON form directly:
+//
+//	{"op": "replace", "path": "/Actors/0/age", "value": 57}
+type Operation struct {
+	Op    string      `json:"op"`   // One of "add", "remove", "replace", "move", "copy" or "test".
+	Path  string      `json:"path"` // Target location.
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// MarshalJSON only includes the members that are used by the operation,
+// so that a null Value is not omitted where it's required.
+func (o Operation) MarshalJSON() ([]byte, error) {
+	m := map[string]interface{}{"op": o.Op, "path": o.Path}
+	switch o.Op {
+	case "add", "replace", "test":
+		m["value"] = o.Value
+	case "move", "copy":
+		m["from"] = o.From
+	}
+	return json.Marshal(m)
+}
+
+// PatchError describes an operation that ApplyPatch failed to apply.
+type PatchError struct {
+	Index int // Index of the operation in the patch.
+	Op    Operation
+	Err   error
+}
+
+func (e *PatchError) Error() string {
+	return fmt.Sprintf("jsonwalk: patch operation %d (%v %q): %v", e.Index, e.Op.Op, e.Op.Path, e.Err)
+}
+
+func (e *PatchError) Unwrap() error {
+	return e.Err
+}
+
+// PatchOptions controls the patch produced by CreatePatchWith. A nil *PatchOptions is the same as the zero value.
+type PatchOptions struct {
+	// Tests precedes every "remove" and "replace" operation with a "test" operation checking the old value,
+	// so that the patch fails to apply to a document that has changed since.
+	Tests bool
+}
+
+// CreatePatch returns a JSON Patch that turns tree a into tree b when applied with ApplyPatch.
+//
+// Map members are compared in the sorted order. A member that is removed and one that is added
+// with the same value in the same Map produce a "move", and an added member with the same value as
+// an unchanged member of the same Map produces a "copy". Arrays are compared by finding the longest
+// common subsequence of equal elements, so that a single inserted element is an "add", not a "replace"
+// of every following element.
+func CreatePatch(a, b *interface{}) []Operation {
+	return CreatePatchWith(a, b, nil)
+}
+
+// CreatePatchWith does the same as CreatePatch with additional options.
+func CreatePatchWith(a, b *interface{}, opts *PatchOptions) []Operation {
+	p := patcher{}
+	if opts != nil {
+		p.o = *opts
+	}
+	p.diff(nil, *a, *b)
+	return p.ops
+}
+
+type patcher struct {
+	o   PatchOptions
+	ops []Operation
+}
+
+func pointer(tokens []string) string {
+	var b strings.Builder
+	for _, t := range tokens {
+		b.WriteByte('/')
+		b.WriteString(escapePointerToken(t))
+	}
+	return b.String()
+}
+
+func child(tokens []string, token string) []string {
+	return append(tokens[:len(tokens):len(tokens)], token)
+}
+
+func (p *patcher) test(path string, old interface{}) {
+	if p.o.Tests {
+		p.ops = append(p.ops, Operation{Op: "test", Path: path, Value: deepCopy(old)})
+	}
+}
+
+func (p *patcher) diff(tokens []string, a, b interface{}) {
+	at, bt := t(a), t(b)
+	if at == bt && at == Map {
+		p.diffMap(tokens, a.(map[string]interface{}), b.(map[string]interface{}))
+		return
+	}
+	if at == bt && at == Array {
+		p.diffArray(tokens, a.([]interface{}), b.([]interface{}))
+		return
+	}
+	if at != bt || a != b {
+		path := pointer(tokens)
+		p.test(path, a)
+		p.ops = append(p.ops, Operation{Op: "replace", Path: path, Value: deepCopy(b)})
+	}
+}
+
+func (p *patcher) diffMap(tokens []string, a, b map[string]interface{}) {
+	var removed, added, common []string
+	for _, k := range maps.Keys(a) {
+		if _, ok := b[k]; ok {
+			common = append(common, k)
+		} else {
+			removed = append(removed, k)
+		}
+	}
+	for _, k := range maps.Keys(b) {
+		if _, ok := a[k]; !ok {
+			added = append(added, k)
+		}
+	}
+	slices.Sort(removed)
+	slices.Sort(added)
+	slices.Sort(common)
+
+	for _, k := range common {
+		p.diff(child(tokens, k), a[k], b[k])
+	}
+	moved := map[string]bool{} // removed keys that have been moved
+	for _, k := range added {
+		path := pointer(child(tokens, k))
+		if from, ok := findKey(removed, moved, a, b[k]); ok {
+			moved[from] = true
+			p.ops = append(p.ops, Operation{Op: "move", From: pointer(child(tokens, from)), Path: path})
+			continue
+		}
+		if from, ok := findUnchanged(common, a, b, b[k]); ok {
+			p.ops = append(p.ops, Operation{Op: "copy", From: pointer(child(tokens, from)), Path: path})
+			continue
+		}
+		p.ops = append(p.ops, Operation{Op: "add", Path: path, Value: deepCopy(b[k])})
+	}
+	for _, k := range removed {
+		if !moved[k] {
+			path := pointer(child(tokens, k))
+			p.test(path, a[k])
+			p.ops = append(p.ops, Operation{Op: "remove", Path: path})
+		}
+	}
+}
+
+// findKey returns the first of keys not yet used, which value in m equals v.
+func findKey(keys []string, used map[string]bool, m map[string]interface{}, v interface{}) (string, bool) {
+	for _, k := range keys {
+		if !used[k] && deepEqual(m[k], v) {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// findUnchanged returns the first of the common keys which value is the same in a, b and equals v.
+// Only containers and strings are worth copying.
+func findUnchanged(common []string, a, b map[string]interface{}, v interface{}) (string, bool) {
+	switch t(v) {
+	case Nil, Bool, Float64:
+		return "", false
+	}
+	for _, k := range common {
+		if deepEqual(a[k], v) && deepEqual(b[k], v) {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+func (p *patcher) diffArray(tokens []string, a, b []interface{}) {
+	// While the operations are applied the array consists of b[:k] followed by the remaining elements of a.
+	k := 0
+	alignLCS(a, b, func(ai, aj, bi, bj int) {
+		for ; ai < aj && bi < bj; ai, bi = ai+1, bi+1 {
+			p.diff(child(tokens, strconv.Itoa(k)), a[ai], b[bi])
+			k++
+		}
+		for ; ai < aj; ai++ {
+			path := pointer(child(tokens, strconv.Itoa(k)))
+			p.test(path, a[ai])
+			p.ops = append(p.ops, Operation{Op: "remove", Path: path})
+		}
+		for ; bi < bj; bi++ {
+			p.ops = append(p.ops, Operation{Op: "add", Path: pointer(child(tokens, strconv.Itoa(k))), Value: deepCopy(b[bi])})
+			k++
+		}
+	}, func(i, j int) {
+		k++
+	})
+}
+
+// ApplyPatch applies the JSON Patch operations to the tree at root, in order.
+//
+// The patch is applied atomically: if any of the operations fails, including a "test" operation
+// finding a different value, root is left untouched and a *PatchError is returned.
+func ApplyPatch(root *interface{}, ops []Operation) error {
+	doc := deepCopy(*root)
+	for i, op := range ops {
+		var err error
+		doc, err = applyOp(doc, op)
+		if err != nil {
+			return &PatchError{Index: i, Op: op, Err: err}
+		}
+	}
+	*root = doc
+	return nil
+}
+
+func applyOp(doc interface{}, op Operation) (interface{}, error) {
+	tokens, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+	switch op.Op {
+	case "add":
+		return pointerAdd(doc, tokens, deepCopy(op.Value))
+	case "remove":
+		if len(tokens) == 0 {
+			return nil, errors.New("can't remove the root")
+		}
+		return pointerRemove(doc, tokens)
+	case "replace":
+		if _, err := pointerGet(doc, tokens); err != nil {
+			return nil, err
+		}
+		return pointerReplace(doc, tokens, deepCopy(op.Value))
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		v, err := pointerGet(doc, from)
+		if err != nil {
+			return nil, fmt.Errorf("from %q: %w", op.From, err)
+		}
+		if op.Op == "copy" {
+			return pointerAdd(doc, tokens, deepCopy(v))
+		}
+		if len(from) < len(tokens) && slices.Equal(from, tokens[:len(from)]) {
+			return nil, fmt.Errorf("can't move %q into its own child", op.From)
+		}
+		if slices.Equal(from, tokens) {
+			return doc, nil
+		}
+		doc, err = pointerRemove(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return pointerAdd(doc, tokens, v)
+	case "test":
+		v, err := pointerGet(doc, tokens)
+		if err != nil {
+			return nil, err
+		}
+		if !deepEqual(v, op.Value) {
+			return nil, fmt.Errorf("%w: found %v", ErrTestFailed, compactJSON(v))
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("unknown operation %q", op.Op)
+}
+
+// arrayIndex parses a JSON Pointer array index. The index is allowed to be equal to n if end is true.
+func arrayIndex(token string, n int, end bool) (int, error) {
+	if end && token == "-" {
+		return n, nil
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || strconv.Itoa(i) != token {
+		return 0, fmt.Errorf("%w: invalid array index %q", ErrNotFound, token)
+	}
+	if i > n || i == n && !end {
+		return 0, fmt.Errorf("%w: array index %d out of range", ErrNotFound, i)
+	}
+	return i, nil
+}
+
+// pointerGet returns the value of doc at the location of tokens.
+func pointerGet(doc interface{}, tokens []string) (interface{}, error) {
+	for _, tok := range tokens {
+		switch n := doc.(type) {
+		case map[string]interface{}:
+			v, ok := n[tok]
+			if !ok {
+				return nil, fmt.Errorf("%w: no member %q", ErrNotFound, tok)
+			}
+			doc = v
+		case []interface{}:
+			i, err := arrayIndex(tok, len(n), false)
+			if err != nil {
+				return nil, err
+			}
+			doc = n[i]
+		default:
+			return nil, fmt.Errorf("%w: %v has no member %q", ErrNotFound, t(doc), tok)
+		}
+	}
+	return doc, nil
+}
+
+// pointerUpdate calls f with the container holding the last of tokens and stores the container returned by f
+// in place of the original one. It returns the updated doc.
+func pointerUpdate(doc interface{}, tokens []string, f func(container interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(tokens) == 1 {
+		return f(doc, tokens[0])
+	}
+	switch n := doc.(type) {
+	case map[string]interface{}:
+		v, ok := n[tokens[0]]
+		if !ok {
+			return nil, fmt.Errorf("%w: no member %q", ErrNotFound, tokens[0])
+		}
+		v, err := pointerUpdate(v, tokens[1:], f)
+		if err != nil {
+			return nil, err
+		}
+		n[tokens[0]] = v
+		return n, nil
+	case []interface{}:
+		i, err := arrayIndex(tokens[0], len(n), false)
+		if err != nil {
+			return nil, err
+		}
+		v, err := pointerUpdate(n[i], tokens[1:], f)
+		if err != nil {
+			return nil, err
+		}
+		n[i] = v
+		return n, nil
+	}
+	return nil, fmt.Errorf("%w: %v has no member %q", ErrNotFound, t(doc), tokens[0])
+}
+
+func pointerAdd(doc interface{}, tokens []string, v interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return v, nil
+	}
+	return pointerUpdate(doc, tokens, func(container interface{}, token string) (interface{}, error) {
+		switch n := container.(type) {
+		case map[string]interface{}:
+			n[token] = v
+			return n, nil
+		case []interface{}:
+			i, err := arrayIndex(token, len(n), true)
+			if err != nil {
+				return nil, err
+			}
+			return slices.Insert(n, i, v), nil
+		}
+		return nil, fmt.Errorf("%w: %v has no member %q", ErrNotFound, t(container), token)
+	})
+}
+
+func pointerRemove(doc interface{}, tokens []string) (interface{}, error) {
+	return pointerUpdate(doc, tokens, func(container interface{}, token string) (interface{}, error) {
+		switch n := container.(type) {
+		case map[string]interface{}:
+			if _, ok := n[token]; !ok {
+				return nil, fmt.Errorf("%w: no member %q", ErrNotFound, token)
+			}
+			delete(n, token)
+			return n, nil
+		case []interface{}:
+			i, err := arrayIndex(token, len(n), false)
+			if err != nil {
+				return nil, err
+			}
+			return slices.Delete(n, i, i+1), nil
+		}
+		return nil, fmt.Errorf("%w: %v has no member %q", ErrNotFound, t(container), token)
+	})
+}
+
+func pointerReplace(doc interface{}, tokens []string, v interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return v, nil
+	}
+	return pointerUpdate(doc, tokens, func(container interface{}, token string) (interface{}, error) {
+		switch n := container.(type) {
+		case map[string]interface{}:
+			n[token] = v
+			return n, nil
+		case []interface{}:
+			i, err := arrayIndex(token, len(n), false)
+			if err != nil {
+				return nil, err
+			}
+			n[i] = v
+			return n, nil
+		}
+		return nil, fmt.Errorf("%w: %v has no member %q", ErrNotFound, t(container), token)
+	})
+}
@@ -0,0 +1,144 @@
+package jsonwalk_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/zzwx/jsonwalk"
+)
+
+func ExampleCreatePatch() {
+	var a, b interface{}
+	err := json.Unmarshal([]byte(`{"name": "Tom", "age": 56, "wife": null, "children": ["Suri", "Connor"]}`), &a)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal([]byte(`{"name": "Tom", "age": 57, "spouse": null, "children": ["Suri", "Isabella", "Connor"]}`), &b)
+	if err != nil {
+		return
+	}
+	patch := jsonwalk.CreatePatch(&a, &b)
+	for _, op := range patch {
+		s, _ := json.Marshal(op)
+		fmt.Println(string(s))
+	}
+	err = jsonwalk.ApplyPatch(&a, patch)
+	fmt.Println(err, reflect.DeepEqual(a, b))
+	// Output:
+	// {"op":"replace","path":"/age","value":57}
+	// {"op":"add","path":"/children/1","value":"Isabella"}
+	// {"from":"/wife","op":"move","path":"/spouse"}
+	// <nil> true
+}
+
+func TestPatchRoundTrip(t *testing.T) {
+	pairs := [][2]string{
+		{actorsJSON, `{"Actors": [{"name": "Robert Downey Jr.", "children": []}, {"name": "Tom Cruise", "age": 57}], "count": 2}`},
+		{`[1, 2, 3, 4, 5]`, `[0, 1, 2, 9, 4, 5, 6]`},
+		{`[1, [2, 3], {"a": [4]}]`, `[[2, 3, 5], {"a": [4, {"b": null}]}]`},
+		{`{"a": {"b": [1, 2]}, "c": "x"}`, `{"a": {"b": [1, 2]}, "c": "x", "d": {"b": [1, 2]}, "a~/b": 1}`},
+		{`{"a": 1}`, `[1]`},
+		{`"abc"`, `null`},
+	}
+	for _, p := range pairs {
+		var a, b interface{}
+		if err := json.Unmarshal([]byte(p[0]), &a); err != nil {
+			t.Errorf("error umarshalling json: %v", err)
+			continue
+		}
+		if err := json.Unmarshal([]byte(p[1]), &b); err != nil {
+			t.Errorf("error umarshalling json: %v", err)
+			continue
+		}
+		for _, opts := range []*jsonwalk.PatchOptions{nil, {Tests: true}} {
+			patch := jsonwalk.CreatePatchWith(&a, &b, opts)
+
+			// Make sure the patch survives marshalling.
+			s, err := json.Marshal(patch)
+			if err != nil {
+				t.Errorf("error marshalling patch: %v", err)
+				continue
+			}
+			var ops []jsonwalk.Operation
+			if err := json.Unmarshal(s, &ops); err != nil {
+				t.Errorf("error umarshalling patch: %v", err)
+				continue
+			}
+
+			var c interface{}
+			_ = json.Unmarshal([]byte(p[0]), &c)
+			if err := jsonwalk.ApplyPatch(&c, ops); err != nil {
+				t.Errorf("%v -> %v: %v", p[0], p[1], err)
+				continue
+			}
+			if !reflect.DeepEqual(c, b) {
+				t.Errorf("%v -> %v with %s: got %v", p[0], p[1], s, c)
+			}
+		}
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	// Examples from RFC 6902, Appendix A.
+	for _, c := range []struct {
+		doc, patch, expected string
+	}{
+		{`{"foo": "bar"}`, `[{"op": "add", "path": "/baz", "value": "qux"}]`, `{"baz": "qux", "foo": "bar"}`},
+		{`{"foo": ["bar", "baz"]}`, `[{"op": "add", "path": "/foo/1", "value": "qux"}]`, `{"foo": ["bar", "qux", "baz"]}`},
+		{`{"baz": "qux", "foo": "bar"}`, `[{"op": "remove", "path": "/baz"}]`, `{"foo": "bar"}`},
+		{`{"foo": ["bar", "qux", "baz"]}`, `[{"op": "remove", "path": "/foo/1"}]`, `{"foo": ["bar", "baz"]}`},
+		{`{"baz": "qux", "foo": "bar"}`, `[{"op": "replace", "path": "/baz", "value": "boo"}]`, `{"baz": "boo", "foo": "bar"}`},
+		{`{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`, `[{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"}]`,
+			`{"foo": {"bar": "baz"}, "qux": {"corge": "grault", "thud": "fred"}}`},
+		{`{"foo": ["all", "grass", "cows", "eat"]}`, `[{"op": "move", "from": "/foo/1", "path": "/foo/3"}]`, `{"foo": ["all", "cows", "eat", "grass"]}`},
+		{`{"baz": "qux", "foo": ["a", 2, "c"]}`, `[{"op": "test", "path": "/baz", "value": "qux"}, {"op": "test", "path": "/foo/1", "value": 2}]`,
+			`{"baz": "qux", "foo": ["a", 2, "c"]}`},
+		{`{"foo": "bar"}`, `[{"op": "add", "path": "/child", "value": {"grandchild": {}}}]`, `{"foo": "bar", "child": {"grandchild": {}}}`},
+		{`{"foo": ["bar"]}`, `[{"op": "add", "path": "/foo/-", "value": ["abc", "def"]}]`, `{"foo": ["bar", ["abc", "def"]]}`},
+		{`{"/": 9, "~1": 10}`, `[{"op": "test", "path": "/~01", "value": 10}, {"op": "copy", "from": "/~1", "path": "/x"}]`, `{"/": 9, "~1": 10, "x": 9}`},
+	} {
+		var doc, expected interface{}
+		var ops []jsonwalk.Operation
+		_ = json.Unmarshal([]byte(c.doc), &doc)
+		_ = json.Unmarshal([]byte(c.expected), &expected)
+		if err := json.Unmarshal([]byte(c.patch), &ops); err != nil {
+			t.Errorf("error umarshalling patch: %v", err)
+			continue
+		}
+		if err := jsonwalk.ApplyPatch(&doc, ops); err != nil {
+			t.Errorf("%v: %v", c.patch, err)
+			continue
+		}
+		if !reflect.DeepEqual(doc, expected) {
+			t.Errorf("%v: expected %v, got %v", c.patch, expected, doc)
+		}
+	}
+
+	for _, c := range []struct {
+		patch string
+		err   error
+	}{
+		{`[{"op": "replace", "path": "/baz", "value": 1}, {"op": "test", "path": "/baz", "value": "qux"}]`, jsonwalk.ErrTestFailed},
+		{`[{"op": "remove", "path": "/baz"}, {"op": "remove", "path": "/baz"}]`, jsonwalk.ErrNotFound},
+		{`[{"op": "add", "path": "/foo/5", "value": 1}]`, jsonwalk.ErrNotFound},
+		{`[{"op": "add", "path": "/baz/bat", "value": 1}]`, jsonwalk.ErrNotFound},
+		{`[{"op": "move", "from": "/foo", "path": "/foo/0"}]`, nil},
+		{`[{"op": "frobnicate", "path": "/baz"}]`, nil},
+	} {
+		var doc interface{}
+		_ = json.Unmarshal([]byte(`{"baz": "qux", "foo": ["a", 2, "c"]}`), &doc)
+		var ops []jsonwalk.Operation
+		_ = json.Unmarshal([]byte(c.patch), &ops)
+		err := jsonwalk.ApplyPatch(&doc, ops)
+		var perr *jsonwalk.PatchError
+		if !errors.As(err, &perr) || c.err != nil && !errors.Is(err, c.err) {
+			t.Errorf("%v: expected %v, got %v", c.patch, c.err, err)
+		}
+		if s, _ := json.Marshal(doc); string(s) != `{"baz":"qux","foo":["a",2,"c"]}` {
+			t.Errorf("%v: document should be untouched, got %s", c.patch, s)
+		}
+	}
+}
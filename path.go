@@ -0,0 +1,267 @@
+package jsonwalk
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// pathSeg is a single element of a path: either a map key or an array index.
+// Parsed patterns may also contain wildcards.
+type pathSeg struct {
+	key     string
+	index   int
+	isIndex bool
+	wild    bool // "*" for any key, "[*]" for any index
+	deep    bool // "**" for any number of elements
+}
+
+func (s pathSeg) String() string {
+	switch {
+	case s.deep:
+		return "**"
+	case s.wild && s.isIndex:
+		return "[*]"
+	case s.wild:
+		return "*"
+	case s.isIndex:
+		return "[" + strconv.Itoa(s.index) + "]"
+	}
+	return s.key
+}
+
+// segmenter is implemented by WalkPath values that are able to provide their elements without parsing Path().
+type segmenter interface {
+	segments() []pathSeg
+}
+
+// pathSegments returns the elements of path p. Paths implemented outside of this package
+// are parsed from their Path() representation.
+func pathSegments(p WalkPath) []pathSeg {
+	if s, ok := p.(segmenter); ok {
+		return s.segments()
+	}
+	segs, err := parsePath(p.Path(), ".", false)
+	if err != nil {
+		return nil
+	}
+	return segs
+}
+
+// PathSyntax selects a string representation of a path.
+type PathSyntax int
+
+const (
+	DottedPath  PathSyntax = iota // "."-separated keys and "[int]" indices as returned by WalkPath.Path(): Actors[0].Born At
+	EscapedPath                   // Same as DottedPath with "\" escaping of ".", "[", "]", "*" and "\" inside keys: a\.b[0]
+	PointerPath                   // JSON Pointer (RFC 6901): /Actors/0/Born At
+)
+
+// FormatPath returns path p in the requested syntax.
+func FormatPath(p WalkPath, syntax PathSyntax) string {
+	return formatPath(pathSegments(p), syntax, ".")
+}
+
+// formatPath renders path elements in the requested syntax using sep to separate keys for
+// DottedPath and EscapedPath.
+func formatPath(segs []pathSeg, syntax PathSyntax, sep string) string {
+	var b strings.Builder
+	for i, s := range segs {
+		if syntax == PointerPath {
+			b.WriteByte('/')
+			if s.isIndex {
+				b.WriteString(strconv.Itoa(s.index))
+			} else {
+				b.WriteString(escapePointerToken(s.key))
+			}
+			continue
+		}
+		if s.isIndex {
+			b.WriteString(s.String())
+			continue
+		}
+		if i > 0 {
+			b.WriteString(sep)
+		}
+		if syntax == EscapedPath {
+			b.WriteString(escapeKey(s.key, sep))
+		} else {
+			b.WriteString(s.key)
+		}
+	}
+	return b.String()
+}
+
+// escapeKey escapes characters of a map key that would otherwise be treated as a part of the path syntax.
+func escapeKey(k string, sep string) string {
+	if !strings.ContainsAny(k, `[]*\`) && !strings.Contains(k, sep) {
+		return k
+	}
+	var b strings.Builder
+	for i := 0; i < len(k); i++ {
+		if sep != "" && strings.HasPrefix(k[i:], sep) {
+			b.WriteByte('\\')
+			b.WriteString(sep)
+			i += len(sep) - 1
+			continue
+		}
+		switch k[i] {
+		case '[', ']', '*', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteByte(k[i])
+	}
+	return b.String()
+}
+
+// escapePointerToken escapes a JSON Pointer reference token as per RFC 6901.
+func escapePointerToken(k string) string {
+	if !strings.ContainsAny(k, "~/") {
+		return k
+	}
+	return strings.ReplaceAll(strings.ReplaceAll(k, "~", "~0"), "/", "~1")
+}
+
+// parsePointer splits a JSON Pointer into its unescaped reference tokens.
+// An empty string is the root pointer without any tokens.
+func parsePointer(p string) ([]string, error) {
+	if p == "" {
+		return nil, nil
+	}
+	if p[0] != '/' {
+		return nil, fmt.Errorf("jsonwalk: JSON Pointer %q doesn't start with \"/\"", p)
+	}
+	tokens := strings.Split(p[1:], "/")
+	for i, t := range tokens {
+		if strings.Contains(t, "~") {
+			for j := 0; j < len(t); j++ {
+				if t[j] == '~' && (j+1 == len(t) || t[j+1] != '0' && t[j+1] != '1') {
+					return nil, fmt.Errorf("jsonwalk: invalid escape in JSON Pointer %q", p)
+				}
+			}
+			tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+		}
+	}
+	return tokens, nil
+}
+
+// parsePath parses a path in the form produced by WalkPath.Path(): sep-separated map keys and "[int]" array indices,
+// such as "Actors[0].children[1]". An empty string is the root path.
+//
+// In escaped form a backslash makes the following character (or the separator) a part of the key, so that
+// keys containing the separator, "[", "]", "*" or "\" can be expressed.
+//
+// Empty keys are recognized between two separators as well as before the first or after the last separator.
+// An empty key can't be the only element of the path, since it's indistinguishable from the root.
+//
+// A "*" key, a "[*]" index and a "**" element are parsed as wildcards matching any key,
+// any index or any number of elements respectively.
+func parsePath(p string, sep string, escaped bool) ([]pathSeg, error) {
+	if sep == "" {
+		sep = "."
+	}
+	var segs []pathSeg
+	i := 0
+	expectKey := true // a key is allowed at the current position
+	for i < len(p) {
+		switch {
+		case p[i] == '[':
+			end := strings.IndexByte(p[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("jsonwalk: unterminated index in path %q", p)
+			}
+			idx := p[i+1 : i+end]
+			if idx == "*" {
+				segs = append(segs, pathSeg{isIndex: true, wild: true})
+			} else {
+				n, err := strconv.Atoi(idx)
+				if err != nil || n < 0 || idx[0] == '+' {
+					return nil, fmt.Errorf("jsonwalk: invalid index %q in path %q", idx, p)
+				}
+				segs = append(segs, pathSeg{index: n, isIndex: true})
+			}
+			i += end + 1
+			expectKey = false
+		case strings.HasPrefix(p[i:], sep):
+			if i == 0 {
+				// An empty first key, such as in ".a"
+				segs = append(segs, pathSeg{})
+			}
+			i += len(sep)
+			expectKey = true
+			if i == len(p) || strings.HasPrefix(p[i:], sep) || p[i] == '[' {
+				// An empty key, such as in "a..b" or "a."
+				segs = append(segs, pathSeg{})
+			}
+		default:
+			if !expectKey {
+				return nil, fmt.Errorf("jsonwalk: missing separator at %d in path %q", i, p)
+			}
+			var b strings.Builder
+			raw := true // no escaped characters in the key
+			for i < len(p) && p[i] != '[' && !strings.HasPrefix(p[i:], sep) {
+				if escaped && p[i] == '\\' && i+1 < len(p) {
+					i++
+					raw = false
+					if strings.HasPrefix(p[i:], sep) {
+						b.WriteString(sep)
+						i += len(sep)
+						continue
+					}
+				} else if p[i] == ']' {
+					return nil, fmt.Errorf("jsonwalk: unexpected ']' at %d in path %q", i, p)
+				}
+				b.WriteByte(p[i])
+				i++
+			}
+			key := b.String()
+			switch {
+			case raw && key == "*":
+				segs = append(segs, pathSeg{wild: true})
+			case raw && key == "**":
+				segs = append(segs, pathSeg{deep: true})
+			default:
+				segs = append(segs, pathSeg{key: key})
+			}
+			expectKey = false
+		}
+	}
+	return segs, nil
+}
+
+// matchSegs reports whether path elements segs match the pattern.
+func matchSegs(pattern, segs []pathSeg) bool {
+	for len(pattern) > 0 {
+		p := pattern[0]
+		if p.deep {
+			for i := 0; i <= len(segs); i++ {
+				if matchSegs(pattern[1:], segs[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segs) == 0 {
+			return false
+		}
+		s := segs[0]
+		if p.isIndex != s.isIndex {
+			return false
+		}
+		if !p.wild && (p.isIndex && p.index != s.index || !p.isIndex && p.key != s.key) {
+			return false
+		}
+		pattern, segs = pattern[1:], segs[1:]
+	}
+	return len(segs) == 0
+}
+
+// hasWildcards reports whether the pattern can match more than one path.
+func hasWildcards(pattern []pathSeg) bool {
+	for _, p := range pattern {
+		if p.wild || p.deep {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,212 @@
+package jsonwalk
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// MaxSchemaExamples is the maximum number of distinct example values InferSchema keeps for a node.
+const MaxSchemaExamples = 3
+
+// Schema is a structure of sample documents inferred by InferSchema.
+//
+// Every Schema node describes all the values found at the same generalized path,
+// which is a path with array indices collapsed to "[*]", such as "Actors[*].children[*]".
+type Schema struct {
+	// Path is the generalized path of the node in the EscapedPath syntax.
+	Path string
+	// Count is how many values were found at the path.
+	Count int
+	// Types counts the values found at the path by their type.
+	Types map[NodeValueType]int
+	// Optional is set for a Map member that is missing from some of the Maps found at the parent path.
+	Optional bool
+	// Examples are up to MaxSchemaExamples distinct leaf values found at the path, in the order of discovery.
+	Examples []interface{}
+	// Integers counts the numbers found at the path that have no fractional part.
+	Integers int
+	// Formats counts the strings found at the path by their detected format: "date-time", "uri" or "email".
+	Formats map[string]int
+	// Properties describe the members of the Maps found at the path.
+	Properties map[string]*Schema
+	// Items describes the elements of all the Arrays found at the path.
+	Items *Schema
+}
+
+// InferSchema walks the sample documents and returns the Schema describing all of them:
+//
+//	s := jsonwalk.InferSchema(&doc1, &doc2)
+//	jsonwalk.FprintSchema(os.Stdout, s)
+func InferSchema(docs ...*interface{}) *Schema {
+	root := &Schema{Types: map[NodeValueType]int{}}
+	for _, doc := range docs {
+		WalkSorted(doc, Callback(func(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType) {
+			root.node(pathSegments(path)).observe(value, nodeValueType)
+		}))
+	}
+	root.markOptional()
+	return root
+}
+
+// node returns the descendant of s at path segs, creating the missing nodes.
+func (s *Schema) node(segs []pathSeg) *Schema {
+	n := s
+	for i, seg := range segs {
+		var child *Schema
+		if seg.isIndex {
+			child = n.Items
+		} else {
+			child = n.Properties[seg.key]
+		}
+		if child == nil {
+			child = &Schema{
+				Path:  formatPath(generalize(segs[:i+1]), EscapedPath, "."),
+				Types: map[NodeValueType]int{},
+			}
+			if seg.isIndex {
+				n.Items = child
+			} else {
+				if n.Properties == nil {
+					n.Properties = map[string]*Schema{}
+				}
+				n.Properties[seg.key] = child
+			}
+		}
+		n = child
+	}
+	return n
+}
+
+// generalize returns a copy of segs with array indices replaced by "[*]".
+func generalize(segs []pathSeg) []pathSeg {
+	g := make([]pathSeg, len(segs))
+	for i, s := range segs {
+		if s.isIndex {
+			s = pathSeg{isIndex: true, wild: true}
+		}
+		g[i] = s
+	}
+	return g
+}
+
+func (s *Schema) observe(value interface{}, nodeValueType NodeValueType) {
+	s.Count++
+	s.Types[nodeValueType]++
+	switch nodeValueType {
+	case Float64:
+		if f := value.(float64); f == math.Trunc(f) && !math.IsInf(f, 0) {
+			s.Integers++
+		}
+	case String:
+		if format := stringFormat(value.(string)); format != "" {
+			if s.Formats == nil {
+				s.Formats = map[string]int{}
+			}
+			s.Formats[format]++
+		}
+	}
+	if nodeValueType == Array || nodeValueType == Map || len(s.Examples) >= MaxSchemaExamples {
+		return
+	}
+	if slices.IndexFunc(s.Examples, func(e interface{}) bool { return deepEqual(e, value) }) < 0 {
+		s.Examples = append(s.Examples, value)
+	}
+}
+
+func (s *Schema) markOptional() {
+	for _, p := range s.Properties {
+		p.Optional = p.Count < s.Types[Map]
+		p.markOptional()
+	}
+	if s.Items != nil {
+		s.Items.markOptional()
+	}
+}
+
+// Lookup returns the node at the generalized path p, such as "Actors[*].name", or nil if there's no such node.
+// Keys containing ".", "[", "]", "*" or "\" are escaped with "\".
+func (s *Schema) Lookup(p string) *Schema {
+	segs, err := parsePath(p, ".", true)
+	if err != nil {
+		return nil
+	}
+	n := s
+	for _, seg := range segs {
+		if seg.isIndex {
+			n = n.Items
+		} else {
+			n = n.Properties[seg.key]
+		}
+		if n == nil {
+			return nil
+		}
+	}
+	return n
+}
+
+// FprintSchema prints s to w in a tree-like format similar to the one of NewOutput:
+//
+//	(m) 2
+//	"Actors" |Actors| (a) 2
+//	  [*] |Actors[*]| (m) 3
+//	    "name" |Actors[*].name| (s) 3 e.g. "Tom Cruise", "Robert Downey Jr."
+//	    "wife" |Actors[*].wife| (n:2 s:1) 3 optional e.g. null, "Nicole"
+//
+// Every line gives the types found at the path, with their counts if there's more than one type,
+// the total count of values, an "optional" mark for optional Map members and the examples.
+// Map members are printed in the sorted order.
+func FprintSchema(w io.Writer, s *Schema) {
+	fprintSchema(w, s, nil, 0)
+}
+
+func fprintSchema(w io.Writer, s *Schema, key interface{}, level int) {
+	var b strings.Builder
+	if level > 0 {
+		b.WriteString(strings.Repeat("  ", level-1))
+		if key == nil {
+			b.WriteString("[*]")
+		} else {
+			_, _ = fmt.Fprintf(&b, "%#v", key)
+		}
+		_, _ = fmt.Fprintf(&b, " |%v| ", s.Path)
+	}
+	types := maps.Keys(s.Types)
+	slices.Sort(types)
+	b.WriteByte('(')
+	for i, nt := range types {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(strings.ToLower(nt.String()[:1]))
+		if len(types) > 1 {
+			_, _ = fmt.Fprintf(&b, ":%d", s.Types[nt])
+		}
+	}
+	_, _ = fmt.Fprintf(&b, ") %d", s.Count)
+	if s.Optional {
+		b.WriteString(" optional")
+	}
+	for i, e := range s.Examples {
+		if i == 0 {
+			b.WriteString(" e.g. ")
+		} else {
+			b.WriteString(", ")
+		}
+		b.WriteString(compactJSON(e))
+	}
+	_, _ = fmt.Fprintln(w, b.String())
+
+	keys := maps.Keys(s.Properties)
+	slices.Sort(keys)
+	for _, k := range keys {
+		fprintSchema(w, s.Properties[k], k, level+1)
+	}
+	if s.Items != nil {
+		fprintSchema(w, s.Items, nil, level+1)
+	}
+}
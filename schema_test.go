@@ -0,0 +1,52 @@
+package jsonwalk_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/zzwx/jsonwalk"
+)
+
+func ExampleInferSchema() {
+	var a, b interface{}
+	err := json.Unmarshal([]byte(`{"Actors": [{"name": "Tom Cruise", "wife": null}, {"name": "Robert Downey Jr.", "wife": "Susan Downey"}]}`), &a)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal([]byte(`{"Actors": [{"name": "Tom Cruise", "age": 56, "children": ["Suri"]}], "total": 1}`), &b)
+	if err != nil {
+		return
+	}
+	jsonwalk.FprintSchema(os.Stdout, jsonwalk.InferSchema(&a, &b))
+	// Output:
+	// (m) 2
+	// "Actors" |Actors| (a) 2
+	//   [*] |Actors[*]| (m) 3
+	//     "age" |Actors[*].age| (f) 1 optional e.g. 56
+	//     "children" |Actors[*].children| (a) 1 optional
+	//       [*] |Actors[*].children[*]| (s) 1 e.g. "Suri"
+	//     "name" |Actors[*].name| (s) 3 e.g. "Tom Cruise", "Robert Downey Jr."
+	//     "wife" |Actors[*].wife| (n:1 s:1) 2 optional e.g. null, "Susan Downey"
+	// "total" |total| (f) 1 optional e.g. 1
+}
+
+func TestInferSchema(t *testing.T) {
+	var f interface{}
+	err := json.Unmarshal([]byte(actorsJSON), &f)
+	if err != nil {
+		t.Errorf("error umarshalling json: %v", err)
+		return
+	}
+	s := jsonwalk.InferSchema(&f)
+	children := s.Lookup("Actors[*].children[*]")
+	if children == nil || children.Count != 6 || children.Types[jsonwalk.String] != 6 || len(children.Examples) != jsonwalk.MaxSchemaExamples {
+		t.Errorf("unexpected children schema: %+v", children)
+	}
+	if wife := s.Lookup("Actors[*].wife"); wife == nil || wife.Optional || wife.Types[jsonwalk.Nil] != 1 || wife.Types[jsonwalk.String] != 1 {
+		t.Errorf("unexpected wife schema: %+v", wife)
+	}
+	if s.Lookup("Actors[0]") != s.Lookup("Actors[*]") || s.Lookup("Actors.missing") != nil {
+		t.Errorf("unexpected lookup results")
+	}
+}
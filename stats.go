@@ -0,0 +1,172 @@
+package jsonwalk
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// PathSize is a size measured for the node at Path.
+type PathSize struct {
+	Path WalkPath
+	Type NodeValueType
+	Size int
+}
+
+// Report is the statistics of a tree returned by Stats.
+type Report struct {
+	// Type is the type of the root.
+	Type NodeValueType
+	// Counts counts the nodes by their type.
+	Counts map[NodeValueType]int
+	// Nodes is the total number of nodes, including the root.
+	Nodes int
+	// MaxDepth is the largest WalkPath.Level() of the nodes, and AvgDepth is the average one.
+	MaxDepth int
+	AvgDepth float64
+	// WidestObject is the Map with the most members, LongestArray is the Array with the most elements
+	// and LargestString is the String with the most bytes. Their Path is nil if there's no node of that type.
+	WidestObject  PathSize
+	LongestArray  PathSize
+	LargestString PathSize
+	// Size is the estimated size of the tree serialized as a compact JSON, in bytes.
+	Size int
+	// Heaviest are the non-root nodes with the largest estimated serialized size, the heaviest first.
+	// A member of a Map includes its key in the size.
+	Heaviest []PathSize
+}
+
+// StatsOptions configures StatsWith. A nil *StatsOptions is the same as the zero value.
+type StatsOptions struct {
+	// Top is the number of Report.Heaviest nodes to report, 10 if 0.
+	Top int
+}
+
+// Stats walks the tree at root once and returns its statistics.
+// It helps answering why a payload grew:
+//
+//	jsonwalk.FprintStats(os.Stdout, jsonwalk.Stats(&f))
+func Stats(root *interface{}) Report {
+	return StatsWith(root, nil)
+}
+
+// StatsWith does the same as Stats, with the options.
+func StatsWith(root *interface{}, opts *StatsOptions) Report {
+	top := 10
+	if opts != nil && opts.Top > 0 {
+		top = opts.Top
+	}
+	r := Report{Counts: map[NodeValueType]int{}}
+	depths := 0
+	type frame struct {
+		PathSize
+		children int
+	}
+	var stack []frame // the node being sized along with its ancestors
+	var sized []PathSize
+	pop := func() {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if len(stack) > 0 {
+			sized = append(sized, n.PathSize)
+			stack[len(stack)-1].Size += n.Size
+		} else {
+			r.Size = n.Size
+		}
+	}
+	WalkSorted(root, Callback(func(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType) {
+		level := path.Level()
+		for len(stack) > level {
+			pop()
+		}
+		r.Nodes++
+		r.Counts[nodeValueType]++
+		depths += level
+		if level > r.MaxDepth {
+			r.MaxDepth = level
+		}
+
+		n := frame{PathSize: PathSize{Path: path, Type: nodeValueType}}
+		if level == 0 {
+			r.Type = nodeValueType
+		} else {
+			if k, ok := key.(string); ok {
+				n.Size += len(appendCanonicalString(nil, k)) + 1 // "key":
+			}
+			parent := &stack[len(stack)-1]
+			if parent.children > 0 {
+				n.Size++ // the comma before all but the first child
+			}
+			parent.children++
+		}
+		switch nodeValueType {
+		case Map:
+			n.Size += 2
+			if m := len(value.(map[string]interface{})); r.WidestObject.Path == nil || m > r.WidestObject.Size {
+				r.WidestObject = PathSize{Path: path, Type: Map, Size: m}
+			}
+		case Array:
+			n.Size += 2
+			if a := len(value.([]interface{})); r.LongestArray.Path == nil || a > r.LongestArray.Size {
+				r.LongestArray = PathSize{Path: path, Type: Array, Size: a}
+			}
+		case String:
+			s := value.(string)
+			n.Size += len(appendCanonicalString(nil, s))
+			if r.LargestString.Path == nil || len(s) > r.LargestString.Size {
+				r.LargestString = PathSize{Path: path, Type: String, Size: len(s)}
+			}
+		default:
+			n.Size += len(appendCanonical(nil, value))
+		}
+		stack = append(stack, n)
+	}))
+	for len(stack) > 0 {
+		pop()
+	}
+	if r.Nodes > 0 {
+		r.AvgDepth = float64(depths) / float64(r.Nodes)
+	}
+	sort.SliceStable(sized, func(i, j int) bool { return sized[i].Size > sized[j].Size })
+	if len(sized) > top {
+		sized = sized[:top]
+	}
+	r.Heaviest = sized
+	return r
+}
+
+// FprintStats prints r to w in a compact format similar to the one of NewOutput:
+//
+//	(m) 28 nodes, 432 bytes
+//	n:1 b:4 s:13 f:4 a:3 m:3
+//	depth max 4, avg 2.96
+//	widest |Actors[0]| (m) 9
+//	longest |Actors[0].children| (a) 3
+//	largest |Actors[1].Born At| (s) 17
+//	heaviest:
+//	  430 |Actors| (a)
+//	  227 |Actors[1]| (m)
+func FprintStats(w io.Writer, r Report) {
+	_, _ = fmt.Fprintf(w, "(%v) %d nodes, %d bytes\n", strings.ToLower(r.Type.String()[:1]), r.Nodes, r.Size)
+	var counts []string
+	for nt := Nil; nt <= Map; nt++ {
+		counts = append(counts, fmt.Sprintf("%v:%d", strings.ToLower(nt.String()[:1]), r.Counts[nt]))
+	}
+	_, _ = fmt.Fprintln(w, strings.Join(counts, " "))
+	_, _ = fmt.Fprintf(w, "depth max %d, avg %.2f\n", r.MaxDepth, r.AvgDepth)
+	for _, ps := range []struct {
+		name string
+		ps   PathSize
+	}{{"widest", r.WidestObject}, {"longest", r.LongestArray}, {"largest", r.LargestString}} {
+		if ps.ps.Path != nil {
+			_, _ = fmt.Fprintf(w, "%v |%v| (%v) %d\n", ps.name, ps.ps.Path.Path(), strings.ToLower(ps.ps.Type.String()[:1]), ps.ps.Size)
+		}
+	}
+	if len(r.Heaviest) > 0 {
+		_, _ = fmt.Fprintln(w, "heaviest:")
+	}
+	for _, ps := range r.Heaviest {
+		_, _ = fmt.Fprintf(w, "  %d |%v| (%v)\n", ps.Size, ps.Path.Path(), strings.ToLower(ps.Type.String()[:1]))
+	}
+}
@@ -0,0 +1,63 @@
+package jsonwalk_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/zzwx/jsonwalk"
+)
+
+func ExampleStats() {
+	var f interface{}
+	err := json.Unmarshal([]byte(actorsJSON), &f)
+	if err != nil {
+		return
+	}
+	jsonwalk.FprintStats(os.Stdout, jsonwalk.StatsWith(&f, &jsonwalk.StatsOptions{Top: 3}))
+	// Output:
+	// (m) 28 nodes, 432 bytes
+	// n:1 b:4 s:13 f:4 a:3 m:3
+	// depth max 4, avg 2.96
+	// widest |Actors[0]| (m) 9
+	// longest |Actors[0].children| (a) 3
+	// largest |Actors[1].Born At| (s) 17
+	// heaviest:
+	//   430 |Actors| (a)
+	//   227 |Actors[1]| (m)
+	//   192 |Actors[0]| (m)
+}
+
+func TestStatsSize(t *testing.T) {
+	for _, src := range []string{`null`, `"a<b"`, `[]`, `{}`, `[1, [2, {}], {"a": {"b": [true, false, null]}, "c": ""}]`, actorsJSON} {
+		var f interface{}
+		if err := json.Unmarshal([]byte(src), &f); err != nil {
+			t.Errorf("error umarshalling json: %v", err)
+			continue
+		}
+		r := jsonwalk.Stats(&f)
+		if expected := len(jsonwalk.Canonicalize(&f)); r.Size != expected {
+			t.Errorf("%v: expected size %v, got %v", src, expected, r.Size)
+		}
+		for i := 1; i < len(r.Heaviest); i++ {
+			if r.Heaviest[i].Size > r.Heaviest[i-1].Size {
+				t.Errorf("%v: heaviest nodes are not sorted: %v", src, r.Heaviest)
+			}
+		}
+	}
+}
+
+func TestStatsHeaviest(t *testing.T) {
+	var f interface{}
+	_ = json.Unmarshal([]byte(`{"a": [1, 22], "b": "x"}`), &f)
+	r := jsonwalk.Stats(&f)
+	// "a":[1,22] is 10 bytes, ,"b":"x" is 8 and ,22 is 3, while the first element 1 has no comma.
+	var got []int
+	for _, h := range r.Heaviest {
+		got = append(got, h.Size)
+	}
+	if r.Heaviest[0].Path.Path() != "a" || fmt.Sprint(got) != "[10 8 3 1]" {
+		t.Errorf("unexpected heaviest nodes: %v", r.Heaviest)
+	}
+}
@@ -0,0 +1,64 @@
+package jsonwalk
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// recordSeparator starts every JSON text of an RFC 7464 JSON text sequence.
+const recordSeparator = 0x1e
+
+// WalkStream walks every JSON value read from r, one after another, each one as its own root.
+// The values may be separated by whitespace or nothing at all, as in the output of `jq -c` or of
+// `docker inspect` run per container, or be the texts of an RFC 7464 JSON text sequence, each one
+// starting with the RS (0x1e) character:
+//
+//	err := jsonwalk.WalkStream(os.Stdin, jsonwalk.Callback(func(path jsonwalk.WalkPath, key interface{}, value interface{}, nodeValueType jsonwalk.NodeValueType) {
+//		i, _ := jsonwalk.DocumentIndex(path)
+//		fmt.Println(i, path.Path())
+//	}))
+//
+// The paths are the same ones Walk gives, starting with "" and the level of 0 for every root, so callbacks
+// producing a document per walk, such as NewJSONWriter, produce one for every value.
+// DocumentIndex returns the index of the value a path belongs to.
+//
+// A value that can't be decoded stops the walk with an error naming its index.
+func WalkStream(r io.Reader, callback WalkCallback) error {
+	dec := json.NewDecoder(rsReader{r})
+	for i := 0; ; i++ {
+		var v interface{}
+		if err := dec.Decode(&v); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("jsonwalk: stream value %d: %w", i, err)
+		}
+		w(walkPath{doc: i + 1}, nil, &v, callback, false)
+	}
+}
+
+// DocumentIndex returns the index, starting with 0, of the value walked by WalkStream that path p belongs to.
+// It returns false for the paths of other walks.
+func DocumentIndex(p WalkPath) (int, bool) {
+	if wp, ok := p.(walkPath); ok && wp.doc > 0 {
+		return wp.doc - 1, true
+	}
+	return 0, false
+}
+
+// rsReader reads r with the RFC 7464 record separators turned into spaces, which json.Decoder skips
+// like any other whitespace between the values.
+type rsReader struct {
+	r io.Reader
+}
+
+func (rr rsReader) Read(p []byte) (int, error) {
+	n, err := rr.r.Read(p)
+	for i, c := range p[:n] {
+		if c == recordSeparator {
+			p[i] = ' '
+		}
+	}
+	return n, err
+}
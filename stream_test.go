@@ -0,0 +1,68 @@
+package jsonwalk_test
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/zzwx/jsonwalk"
+)
+
+func ExampleWalkStream() {
+	input := `{"Name": "web", "State": {"Running": true}}{"Name": "db", "State": {"Running": false}}
+[1, 2] "three"`
+	err := jsonwalk.WalkStream(strings.NewReader(input), jsonwalk.Callback(func(path jsonwalk.WalkPath, key interface{}, value interface{}, nodeValueType jsonwalk.NodeValueType) {
+		if i, ok := jsonwalk.DocumentIndex(path); ok && path.Level() > 0 && nodeValueType != jsonwalk.Map {
+			fmt.Printf("%v: %v = %v\n", i, path.Path(), value)
+		}
+	}))
+	if err != nil {
+		fmt.Println(err)
+	}
+	// Unordered output:
+	// 0: Name = web
+	// 0: State.Running = true
+	// 1: Name = db
+	// 1: State.Running = false
+	// 2: [0] = 1
+	// 2: [1] = 2
+}
+
+func ExampleWalkStream_sequence() {
+	// An RFC 7464 JSON text sequence reformatted as JSON Lines.
+	input := "\x1e{\"a\": [true, null]}\n\x1e\"x\"\n\x1e42\n"
+	jw := jsonwalk.NewJSONWriter(os.Stdout, nil)
+	if err := jsonwalk.WalkStream(strings.NewReader(input), jw); err != nil {
+		fmt.Println(err)
+	}
+	// Output:
+	// {"a":[true,null]}
+	// "x"
+	// 42
+}
+
+func TestWalkStream(t *testing.T) {
+	var roots []int
+	err := jsonwalk.WalkStream(strings.NewReader("1 2\t\n3{}[]\"s\"null"), jsonwalk.Callback(func(path jsonwalk.WalkPath, key interface{}, value interface{}, nodeValueType jsonwalk.NodeValueType) {
+		if path.Level() == 0 {
+			i, _ := jsonwalk.DocumentIndex(path)
+			roots = append(roots, i)
+		}
+	}))
+	if err != nil || fmt.Sprint(roots) != "[0 1 2 3 4 5 6]" {
+		t.Errorf("expected 7 roots, got %v, %v", roots, err)
+	}
+
+	err = jsonwalk.WalkStream(strings.NewReader(`{"a": 1} {"b": }`), nil)
+	if err == nil || !strings.Contains(err.Error(), "stream value 1") {
+		t.Errorf("expected an error for value 1, got %v", err)
+	}
+
+	var f interface{} = map[string]interface{}{"a": 1.0}
+	jsonwalk.Walk(&f, jsonwalk.Callback(func(path jsonwalk.WalkPath, key interface{}, value interface{}, nodeValueType jsonwalk.NodeValueType) {
+		if _, ok := jsonwalk.DocumentIndex(path); ok {
+			t.Errorf("unexpected document index for %v", path.Path())
+		}
+	}))
+}
@@ -0,0 +1,302 @@
+package jsonwalk
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ColumnOrder selects the order of the columns produced by ToTable.
+type ColumnOrder int
+
+const (
+	ColumnsSorted    ColumnOrder = iota // Sorted by their paths, with array indices compared as numbers.
+	ColumnsFirstSeen                    // In the order they first appear in the rows, which are walked by WalkSorted.
+)
+
+// ArrayCells selects how ToTable puts arrays nested in the rows into cells.
+type ArrayCells int
+
+const (
+	ArraysFlatten ArrayCells = iota // Every element gets its own column: children[0], children[1], ...
+	ArraysJoin                      // A single column with the elements joined by TableOptions.Separator.
+	ArraysJSON                      // A single column with the array as a compact JSON.
+)
+
+// TableOptions configures ToTable. A nil *TableOptions is the same as the zero value.
+type TableOptions struct {
+	// Columns lists the columns to produce, in order. All the found columns are produced if it's empty.
+	Columns []string
+	// Order of the found columns when Columns is empty.
+	Order  ColumnOrder
+	Arrays ArrayCells
+	// Separator of the elements for ArraysJoin, ", " if empty.
+	Separator string
+}
+
+// Table is an array of Maps turned into rows and columns by ToTable.
+type Table struct {
+	// Columns are the paths of the leaves relative to the rows, such as "Born At" or "children[0]",
+	// written the same way as the basePath of ToTable: "a.b" is the key "b" nested in "a", "a\.b" is the key "a.b".
+	Columns []string
+	// Rows hold a cell for every column. Cells are empty for the missing values and nulls.
+	Rows [][]string
+}
+
+// ToTable finds the array of Maps at basePath and turns it into a Table with a row for every Map
+// and a column for every leaf path found in any of them:
+//
+//	tbl, err := jsonwalk.ToTable(&f, "Actors", nil)
+//	if err != nil {
+//		return err
+//	}
+//	err = tbl.WriteCSV(os.Stdout)
+//
+// basePath is written the same way WalkPath.Path() returns it, with backslash escaping ".", "[", "]", "*" and "\"
+// inside keys. An empty basePath is the root.
+//
+// Strings are put into cells as is, numbers are formatted the way Canonicalize does, and Maps or Arrays
+// that end up in a single cell are written as compact JSON.
+func ToTable(root *interface{}, basePath string, opts *TableOptions) (*Table, error) {
+	var o TableOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Separator == "" {
+		o.Separator = ", "
+	}
+	segs, err := parsePath(basePath, ".", true)
+	if err != nil {
+		return nil, err
+	}
+	if hasWildcards(segs) {
+		return nil, fmt.Errorf("jsonwalk: table path %q can't have wildcards", basePath)
+	}
+	v := *root
+	for _, seg := range segs {
+		switch n := v.(type) {
+		case map[string]interface{}:
+			if el, ok := n[seg.key]; ok && !seg.isIndex {
+				v = el
+				continue
+			}
+		case []interface{}:
+			if seg.isIndex && seg.index < len(n) {
+				v = n[seg.index]
+				continue
+			}
+		}
+		return nil, fmt.Errorf("jsonwalk: table path %q: %w", basePath, ErrNotFound)
+	}
+	rows, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("jsonwalk: table path %q is a %v rather than an Array", basePath, t(v))
+	}
+
+	type column struct {
+		segs []pathSeg
+		seen int
+	}
+	found := map[string]*column{}
+	cells := make([]map[string]string, len(rows))
+	for i, row := range rows {
+		if t(row) != Map {
+			return nil, fmt.Errorf("jsonwalk: table row %v is a %v rather than a Map", i, t(row))
+		}
+		if o.Arrays != ArraysFlatten {
+			row = collapseArrays(row, &o)
+		}
+		cells[i] = map[string]string{}
+		for _, pv := range Flatten(&row, &FlattenOptions{Syntax: EscapedPath}) {
+			if found[pv.Path] == nil {
+				segs, _ := parsePath(pv.Path, ".", true)
+				found[pv.Path] = &column{segs: segs, seen: len(found)}
+			}
+			cells[i][pv.Path] = tableCell(pv.Value)
+		}
+	}
+
+	tbl := &Table{Columns: o.Columns}
+	if len(tbl.Columns) == 0 {
+		for name := range found {
+			tbl.Columns = append(tbl.Columns, name)
+		}
+		sort.Slice(tbl.Columns, func(i, j int) bool {
+			a, b := found[tbl.Columns[i]], found[tbl.Columns[j]]
+			if o.Order == ColumnsFirstSeen {
+				return a.seen < b.seen
+			}
+			return compareSegs(a.segs, b.segs) < 0
+		})
+	}
+	for _, rc := range cells {
+		row := make([]string, len(tbl.Columns))
+		for j, c := range tbl.Columns {
+			row[j] = rc[c]
+		}
+		tbl.Rows = append(tbl.Rows, row)
+	}
+	return tbl, nil
+}
+
+// PathTable returns a two-column Table of "Path" and "Value" with a row for every leaf of root,
+// as returned by Flatten with opts, which is a handy form for rendering a whole document:
+//
+//	err := jsonwalk.PathTable(&f, nil).WriteMarkdown(os.Stdout)
+func PathTable(root *interface{}, opts *FlattenOptions) *Table {
+	tbl := &Table{Columns: []string{"Path", "Value"}}
+	for _, pv := range Flatten(root, opts) {
+		tbl.Rows = append(tbl.Rows, []string{pv.Path, tableCell(pv.Value)})
+	}
+	return tbl
+}
+
+// collapseArrays returns a copy of v with the arrays replaced by the strings of their single cells.
+func collapseArrays(v interface{}, o *TableOptions) interface{} {
+	switch vt := v.(type) {
+	case []interface{}:
+		if o.Arrays == ArraysJSON {
+			return compactJSON(vt)
+		}
+		els := make([]string, len(vt))
+		for j, el := range vt {
+			els[j] = tableCell(el)
+		}
+		return strings.Join(els, o.Separator)
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(vt))
+		for k, el := range vt {
+			m[k] = collapseArrays(el, o)
+		}
+		return m
+	}
+	return v
+}
+
+// tableCell formats a value for a single cell.
+func tableCell(v interface{}) string {
+	switch vt := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return vt
+	case float64:
+		return string(appendCanonicalNumber(nil, vt))
+	case bool:
+		if vt {
+			return "true"
+		}
+		return "false"
+	}
+	return compactJSON(v)
+}
+
+// compareSegs compares paths element by element, with keys compared as strings, indices as numbers
+// and keys going before indices.
+func compareSegs(a, b []pathSeg) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		x, y := a[i], b[i]
+		switch {
+		case x.isIndex != y.isIndex:
+			if y.isIndex {
+				return -1
+			}
+			return 1
+		case x.isIndex && x.index != y.index:
+			if x.index < y.index {
+				return -1
+			}
+			return 1
+		case !x.isIndex && x.key != y.key:
+			return strings.Compare(x.key, y.key)
+		}
+	}
+	return len(a) - len(b)
+}
+
+// WriteCSV writes the table to w as CSV (RFC 4180) with a header line of the column names.
+func (tbl *Table) WriteCSV(w io.Writer) error {
+	return tbl.write(csv.NewWriter(w))
+}
+
+// WriteTSV writes the table to w as tab-separated values with a header line of the column names.
+// Cells containing tabs, newlines or quotes are quoted the way WriteCSV does.
+func (tbl *Table) WriteTSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = '\t'
+	return tbl.write(cw)
+}
+
+func (tbl *Table) write(cw *csv.Writer) error {
+	if err := cw.Write(tbl.Columns); err != nil {
+		return err
+	}
+	return cw.WriteAll(tbl.Rows) // WriteAll flushes
+}
+
+// WriteMarkdown writes the table to w as a GitHub Flavored Markdown table.
+// Markdown punctuation in the cells is escaped with "\", and newlines are replaced with "<br>".
+func (tbl *Table) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	writeRow := func(cells []string) {
+		b.WriteByte('|')
+		for _, c := range cells {
+			b.WriteByte(' ')
+			b.WriteString(escapeMarkdown(c))
+			b.WriteString(" |")
+		}
+		b.WriteByte('\n')
+	}
+	writeRow(tbl.Columns)
+	b.WriteByte('|')
+	for range tbl.Columns {
+		b.WriteString(" --- |")
+	}
+	b.WriteByte('\n')
+	for _, row := range tbl.Rows {
+		writeRow(row)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// escapeMarkdown escapes the characters of s that would otherwise be treated as Markdown or break the table row.
+func escapeMarkdown(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '\\', '`', '*', '_', '[', ']', '<', '>', '|', '#', '~':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\r': // dropped, so that "\r\n" is a single <br>
+		case '\n':
+			b.WriteString("<br>")
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// WriteHTML writes the table to w as an HTML table with the column names in its head.
+func (tbl *Table) WriteHTML(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("<table>\n<thead>\n<tr>")
+	for _, c := range tbl.Columns {
+		b.WriteString("<th>" + html.EscapeString(c) + "</th>")
+	}
+	b.WriteString("</tr>\n</thead>\n<tbody>\n")
+	for _, row := range tbl.Rows {
+		b.WriteString("<tr>")
+		for _, c := range row {
+			b.WriteString("<td>" + html.EscapeString(c) + "</td>")
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</tbody>\n</table>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,74 @@
+package jsonwalk_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/zzwx/jsonwalk"
+)
+
+func ExampleToTable() {
+	var f interface{}
+	err := json.Unmarshal([]byte(actorsJSON), &f)
+	if err != nil {
+		return
+	}
+	tbl, err := jsonwalk.ToTable(&f, "Actors", &jsonwalk.TableOptions{
+		Columns: []string{"name", "Born At", "children", "wife"},
+		Arrays:  jsonwalk.ArraysJoin,
+	})
+	if err != nil {
+		return
+	}
+	_ = tbl.WriteCSV(os.Stdout)
+	// Output:
+	// name,Born At,children,wife
+	// Tom Cruise,"Syracuse, NY","Suri, Isabella Jane, Connor",
+	// Robert Downey Jr.,"New York City, NY","Indio Falconer, Avri Roel, Exton Elias",Susan Downey
+}
+
+func TestToTable(t *testing.T) {
+	var f interface{}
+	err := json.Unmarshal([]byte(`{"data": {"rows": [
+		{"id": 1, "tags": ["a", "b"], "meta": {"x": true}, "meta.x": "key"},
+		{"id": 2.5, "tags": ["c", "d", "e", "f", "g", "h", "i", "j", "k", "l", "m"], "z": null}
+	]}}`), &f)
+	if err != nil {
+		t.Errorf("error umarshalling json: %v", err)
+		return
+	}
+	for _, test := range []struct {
+		opts     *jsonwalk.TableOptions
+		expected string
+	}{
+		{nil, "id\tmeta.x\tmeta\\.x\ttags[0]\ttags[1]\ttags[2]\ttags[3]\ttags[4]\ttags[5]\ttags[6]\ttags[7]\ttags[8]\ttags[9]\ttags[10]\tz\n" +
+			"1\ttrue\tkey\ta\tb\t\t\t\t\t\t\t\t\t\t\n" +
+			"2.5\t\t\tc\td\te\tf\tg\th\ti\tj\tk\tl\tm\t\n"},
+		{&jsonwalk.TableOptions{Order: jsonwalk.ColumnsFirstSeen, Arrays: jsonwalk.ArraysJSON}, "id\tmeta.x\tmeta\\.x\ttags\tz\n" +
+			"1\ttrue\tkey\t\"[\"\"a\"\",\"\"b\"\"]\"\t\n" +
+			"2.5\t\t\t\"[\"\"c\"\",\"\"d\"\",\"\"e\"\",\"\"f\"\",\"\"g\"\",\"\"h\"\",\"\"i\"\",\"\"j\"\",\"\"k\"\",\"\"l\"\",\"\"m\"\"]\"\t\n"},
+	} {
+		tbl, err := jsonwalk.ToTable(&f, "data.rows", test.opts)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			continue
+		}
+		var b bytes.Buffer
+		if err := tbl.WriteTSV(&b); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if b.String() != test.expected {
+			t.Errorf("expected:\n%v\ngot:\n%v", test.expected, b.String())
+		}
+	}
+
+	if _, err := jsonwalk.ToTable(&f, "data.missing", nil); !errors.Is(err, jsonwalk.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if _, err := jsonwalk.ToTable(&f, "data", nil); err == nil {
+		t.Errorf("expected an error for a Map")
+	}
+}
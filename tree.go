@@ -0,0 +1,112 @@
+package jsonwalk
+
+import "fmt"
+
+// deepCopy returns a copy of v that shares no Array or Map with it.
+func deepCopy(v interface{}) interface{} {
+	switch vt := v.(type) {
+	case []interface{}:
+		a := make([]interface{}, len(vt))
+		for i, el := range vt {
+			a[i] = deepCopy(el)
+		}
+		return a
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(vt))
+		for k, el := range vt {
+			m[k] = deepCopy(el)
+		}
+		return m
+	}
+	return v
+}
+
+// deepEqual reports whether a and b are the same JSON values.
+// Unlike reflect.DeepEqual it doesn't distinguish nil and empty containers.
+func deepEqual(a, b interface{}) bool {
+	switch at := a.(type) {
+	case []interface{}:
+		bt, ok := b.([]interface{})
+		if !ok || len(at) != len(bt) {
+			return false
+		}
+		for i := range at {
+			if !deepEqual(at[i], bt[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		bt, ok := b.(map[string]interface{})
+		if !ok || len(at) != len(bt) {
+			return false
+		}
+		for k, av := range at {
+			bv, ok := bt[k]
+			if !ok || !deepEqual(av, bv) {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
+
+// alignLCS pairs up elements of a and b along their longest common subsequence of equal elements.
+// It calls gap for every run of elements a[ai:aj] and b[bi:bj] between the common ones, including the empty runs,
+// and common for every pair of the common elements, in order.
+func alignLCS(a, b []interface{}, gap func(ai, aj, bi, bj int), common func(i, j int)) {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if deepEqual(a[i], b[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	gi, gj := 0, 0 // start of the current gap
+	for i < len(a) && j < len(b) {
+		switch {
+		case deepEqual(a[i], b[j]):
+			gap(gi, i, gj, j)
+			common(i, j)
+			i++
+			j++
+			gi, gj = i, j
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	gap(gi, len(a), gj, len(b))
+}
+
+// keyIdentity returns a comparable identity of v if it's a Map with a leaf under the key,
+// such as the "name" of the elements of the "Actors" array.
+func keyIdentity(v interface{}, key string) (string, bool) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	id, ok := m[key]
+	if !ok {
+		return "", false
+	}
+	switch tp := t(id); tp {
+	case Array, Map:
+		return "", false
+	default:
+		return fmt.Sprintf("%v:%#v", tp, id), true
+	}
+}
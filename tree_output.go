@@ -0,0 +1,176 @@
+package jsonwalk
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// TreeOptions configures NewTreeOutput. A nil *TreeOptions is the same as the zero value.
+type TreeOptions struct {
+	// ASCII draws the connectors with "|--" and "`--" instead of the Unicode box-drawing characters,
+	// and the ellipses with "..." instead of "…".
+	ASCII bool
+	// FullTypes shows the types as "String" or "Float64" instead of the "s" or "f" hints.
+	FullTypes bool
+	// MaxString truncates the strings longer than that many characters, marking them with "…". 0 means no limit.
+	MaxString int
+	// MaxArray shows only the first MaxArray elements of longer arrays, followed by a "... 120 more" line.
+	// 0 means no limit.
+	MaxArray int
+	// MaxDepth hides the nodes which WalkPath.Level() is greater than MaxDepth, showing the number of hidden children
+	// of the containers at the MaxDepth level instead. 0 means no limit.
+	MaxDepth int
+}
+
+// treeOutput implements WalkLeaveCallback by printing the walked document in the form of the tree command.
+type treeOutput struct {
+	w     io.Writer
+	opts  TreeOptions
+	stack []treeFrame
+}
+
+// treeFrame is an open container which children are being printed.
+type treeFrame struct {
+	level     int
+	prefix    string // printed before the connectors of the children
+	remaining int    // children left to show
+	hidden    int    // children that won't be shown
+}
+
+// NewTreeOutput returns a WalkLeaveCallback that prints the walked document to w as a tree,
+// the way the tree command prints directories:
+//
+//	(m)
+//	├── a (a)
+//	│   ├── [0]: 1 (f)
+//	│   ├── [1] (a)
+//	│   │   ├── [0]: 2 (f)
+//	│   │   └── [1] (m)
+//	│   │       └── b (m)
+//	│   └── [2] (m)
+//	└── c: "d" (s)
+//
+// Open containers are tracked with the leave lifecycle, so the callback has to be passed to Walk directly
+// or through the callbacks that forward WalkLeaveCallback.L, such as Multi, Filter or Limit.
+func NewTreeOutput(w io.Writer, opts *TreeOptions) *treeOutput {
+	o := &treeOutput{w: w}
+	if opts != nil {
+		o.opts = *opts
+	}
+	return o
+}
+
+func (o *treeOutput) connectors() (mid, last, pipe string) {
+	if o.opts.ASCII {
+		return "|-- ", "`-- ", "|   "
+	}
+	return "├── ", "└── ", "│   "
+}
+
+func (o *treeOutput) ellipsis() string {
+	if o.opts.ASCII {
+		return "..."
+	}
+	return "…"
+}
+
+func (o *treeOutput) typeHint(nodeValueType NodeValueType) string {
+	if o.opts.FullTypes {
+		return nodeValueType.String()
+	}
+	return strings.ToLower(nodeValueType.String()[:1])
+}
+
+func (o *treeOutput) C(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType) {
+	level := path.Level()
+	mid, last, pipe := o.connectors()
+	var b strings.Builder
+	childPrefix := ""
+	if level > 0 {
+		if len(o.stack) == 0 || o.stack[len(o.stack)-1].level != level-1 {
+			return // under MaxDepth or under a hidden array element
+		}
+		parent := &o.stack[len(o.stack)-1]
+		if parent.remaining == 0 {
+			return // a hidden array element
+		}
+		parent.remaining--
+		b.WriteString(parent.prefix)
+		if parent.remaining == 0 && parent.hidden == 0 {
+			b.WriteString(last)
+			childPrefix = parent.prefix + "    "
+		} else {
+			b.WriteString(mid)
+			childPrefix = parent.prefix + pipe
+		}
+		if i, ok := key.(int); ok {
+			_, _ = fmt.Fprintf(&b, "[%d]", i)
+		} else {
+			_, _ = fmt.Fprint(&b, key)
+		}
+	}
+
+	children := 0
+	switch nodeValueType {
+	case Array:
+		children = len(value.([]interface{}))
+	case Map:
+		children = len(value.(map[string]interface{}))
+	default:
+		if level > 0 {
+			b.WriteString(": ")
+		}
+		b.WriteString(o.format(value))
+		b.WriteByte(' ')
+	}
+	if level > 0 && (nodeValueType == Array || nodeValueType == Map) {
+		b.WriteByte(' ')
+	}
+	_, _ = fmt.Fprintf(&b, "(%v)", o.typeHint(nodeValueType))
+
+	if nodeValueType == Array || nodeValueType == Map {
+		if o.opts.MaxDepth > 0 && level >= o.opts.MaxDepth {
+			if children > 0 {
+				noun := "elements"
+				if nodeValueType == Map {
+					noun = "members"
+				}
+				_, _ = fmt.Fprintf(&b, " %v %d %v", o.ellipsis(), children, noun)
+			}
+		} else {
+			shown := children
+			if nodeValueType == Array && o.opts.MaxArray > 0 && shown > o.opts.MaxArray {
+				shown = o.opts.MaxArray
+			}
+			o.stack = append(o.stack, treeFrame{level: level, prefix: childPrefix, remaining: shown, hidden: children - shown})
+		}
+	}
+	_, _ = fmt.Fprintln(o.w, b.String())
+}
+
+func (o *treeOutput) L(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType) {
+	if len(o.stack) == 0 || o.stack[len(o.stack)-1].level != path.Level() {
+		return
+	}
+	f := o.stack[len(o.stack)-1]
+	o.stack = o.stack[:len(o.stack)-1]
+	if f.hidden > 0 {
+		_, last, _ := o.connectors()
+		_, _ = fmt.Fprintf(o.w, "%v%v... %d more\n", f.prefix, last, f.hidden)
+	}
+}
+
+func (o *treeOutput) format(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		if r := []rune(v); o.opts.MaxString > 0 && len(r) > o.opts.MaxString {
+			return strconv.Quote(string(r[:o.opts.MaxString])) + o.ellipsis()
+		}
+		return strconv.Quote(v)
+	}
+	return fmt.Sprintf("%#v", value)
+}
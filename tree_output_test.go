@@ -0,0 +1,70 @@
+package jsonwalk_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/zzwx/jsonwalk"
+)
+
+func ExampleNewTreeOutput() {
+	var f interface{}
+	err := json.Unmarshal([]byte(actorsJSON), &f)
+	if err != nil {
+		return
+	}
+	jsonwalk.WalkSorted(&f, jsonwalk.NewTreeOutput(os.Stdout, &jsonwalk.TreeOptions{MaxString: 8, MaxArray: 1}))
+	// Output:
+	// (m)
+	// └── Actors (a)
+	//     ├── [0] (m)
+	//     │   ├── Birthdate: "July 3, "… (s)
+	//     │   ├── Born At: "Syracuse"… (s)
+	//     │   ├── age: 56 (f)
+	//     │   ├── children (a)
+	//     │   │   ├── [0]: "Suri" (s)
+	//     │   │   └── ... 2 more
+	//     │   ├── hasChildren: true (b)
+	//     │   ├── hasGreyHair: false (b)
+	//     │   ├── name: "Tom Crui"… (s)
+	//     │   ├── weight: 67.5 (f)
+	//     │   └── wife: null (n)
+	//     └── ... 1 more
+}
+
+func TestTreeOutput(t *testing.T) {
+	var f interface{}
+	err := json.Unmarshal([]byte(`{"a": [1, [2, {"b": {}}], {}], "c": "d"}`), &f)
+	if err != nil {
+		t.Errorf("error umarshalling json: %v", err)
+		return
+	}
+	for _, test := range []struct {
+		opts     *jsonwalk.TreeOptions
+		expected string
+	}{
+		{nil, "(m)\n├── a (a)\n│   ├── [0]: 1 (f)\n│   ├── [1] (a)\n│   │   ├── [0]: 2 (f)\n│   │   └── [1] (m)\n│   │       └── b (m)\n│   └── [2] (m)\n└── c: \"d\" (s)\n"},
+		{&jsonwalk.TreeOptions{ASCII: true, FullTypes: true, MaxDepth: 2}, "(Map)\n|-- a (Array)\n|   |-- [0]: 1 (Float64)\n|   |-- [1] (Array) ... 2 elements\n|   `-- [2] (Map)\n`-- c: \"d\" (String)\n"},
+		{&jsonwalk.TreeOptions{ASCII: true, MaxDepth: 1}, "(m)\n|-- a (a) ... 3 elements\n`-- c: \"d\" (s)\n"},
+		{&jsonwalk.TreeOptions{MaxDepth: 1, MaxArray: 2}, "(m)\n├── a (a) … 3 elements\n└── c: \"d\" (s)\n"},
+	} {
+		var b bytes.Buffer
+		jsonwalk.WalkSorted(&f, jsonwalk.NewTreeOutput(&b, test.opts))
+		if b.String() != test.expected {
+			t.Errorf("expected:\n%v\ngot:\n%v", test.expected, b.String())
+		}
+	}
+	var s interface{} = "abc"
+	var b bytes.Buffer
+	jsonwalk.Walk(&s, jsonwalk.NewTreeOutput(&b, nil))
+	if b.String() != "\"abc\" (s)\n" {
+		t.Errorf("unexpected root leaf output %q", b.String())
+	}
+	b.Reset()
+	jsonwalk.Walk(&s, jsonwalk.NewTreeOutput(&b, &jsonwalk.TreeOptions{ASCII: true, MaxString: 2}))
+	if b.String() != "\"ab\"... (s)\n" {
+		t.Errorf("unexpected truncated ASCII output %q", b.String())
+	}
+}
@@ -0,0 +1,240 @@
+package jsonwalk
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// ErrConflict is wrapped by UnflattenError when a path requires a node to be of a different type
+// than an already placed value, or when the same path is given twice.
+var ErrConflict = errors.New("conflicting paths")
+
+// ErrIndexRange is wrapped by UnflattenError when a path has an array index greater than FlattenOptions.MaxIndex.
+var ErrIndexRange = errors.New("array index out of range")
+
+// DefaultMaxIndex is the largest array index Unflatten accepts when FlattenOptions.MaxIndex is 0.
+const DefaultMaxIndex = 1<<16 - 1
+
+// UnflattenError describes a path that couldn't be placed into the tree by Unflatten.
+type UnflattenError struct {
+	Path string // The path as it was given.
+	Err  error
+}
+
+func (e *UnflattenError) Error() string {
+	return fmt.Sprintf("jsonwalk: unflatten %q: %v", e.Path, e.Err)
+}
+
+func (e *UnflattenError) Unwrap() error {
+	return e.Err
+}
+
+// hole marks a place in the tree under construction that has not been set yet,
+// such as skipped indices of a sparse array.
+type hole struct{}
+
+// Unflatten is the reverse of Flatten: it builds a tree of map[string]interface{} and []interface{}
+// from the leaves and their paths.
+//
+// Paths are parsed according to the same options that are accepted by Flatten, so that
+//
+//	Actors[0].children[1] = "Isabella Jane"
+//
+// creates a map with an "Actors" array, which first element is a map with a "children" array.
+// Array elements that aren't given any value, such as the first element of "children" above, are set to nil.
+// An empty path sets the root value.
+//
+// JSON Pointer tokens that are non-negative integers are treated as array indices.
+// A single empty key of a root map can only be expressed as a JSON Pointer, for other syntaxes it's the root path.
+//
+// Values have to be made of the types json.Unmarshal produces: nil, bool, string, float64,
+// []interface{} and map[string]interface{}. Other values, such as an int, result in an error.
+//
+// Paths that contradict each other, like "a" = 1 and "a.b" = 2, or "a.b" and "a[0]",
+// result in an error that wraps ErrConflict. Array indices greater than opts.MaxIndex
+// result in an error that wraps ErrIndexRange.
+func Unflatten(pvs []PathValue, opts *FlattenOptions) (interface{}, error) {
+	var o FlattenOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Separator == "" {
+		o.Separator = "."
+	}
+	if o.MaxIndex <= 0 {
+		o.MaxIndex = DefaultMaxIndex
+	}
+	var root interface{} = hole{}
+	for _, pv := range pvs {
+		segs, err := unflattenPath(pv.Path, o)
+		if err != nil {
+			return nil, &UnflattenError{Path: pv.Path, Err: err}
+		}
+		if err := checkValue(pv.Value); err != nil {
+			return nil, &UnflattenError{Path: pv.Path, Err: err}
+		}
+		root, err = place(root, segs, 0, pv.Value, o)
+		if err != nil {
+			return nil, &UnflattenError{Path: pv.Path, Err: err}
+		}
+	}
+	return fillHoles(root), nil
+}
+
+// UnflattenMap does the same as Unflatten for a map of paths to values, such as the one returned by FlattenMap.
+// Paths are placed in the sorted order.
+func UnflattenMap(m map[string]interface{}, opts *FlattenOptions) (interface{}, error) {
+	keys := maps.Keys(m)
+	slices.Sort(keys)
+	pvs := make([]PathValue, len(keys))
+	for i, k := range keys {
+		pvs[i] = PathValue{Path: k, Value: m[k]}
+	}
+	return Unflatten(pvs, opts)
+}
+
+func unflattenPath(p string, o FlattenOptions) ([]pathSeg, error) {
+	if o.Syntax == PointerPath {
+		tokens, err := parsePointer(p)
+		if err != nil {
+			return nil, err
+		}
+		segs := make([]pathSeg, len(tokens))
+		for i, tok := range tokens {
+			if n, err := strconv.Atoi(tok); err == nil && n >= 0 && strconv.Itoa(n) == tok {
+				segs[i] = pathSeg{index: n, isIndex: true}
+			} else {
+				segs[i] = pathSeg{key: tok}
+			}
+		}
+		return segs, nil
+	}
+	segs, err := parsePath(p, o.Separator, o.Syntax == EscapedPath)
+	if err != nil {
+		return nil, err
+	}
+	for i, s := range segs {
+		switch {
+		case s.isIndex && s.wild:
+			return nil, fmt.Errorf("wildcard index is not allowed")
+		case s.wild || s.deep:
+			if o.Syntax == EscapedPath {
+				return nil, fmt.Errorf("wildcard key is not allowed")
+			}
+			// Keys are not escaped in DottedPath, so this is the literal key.
+			segs[i] = pathSeg{key: s.String()}
+		}
+	}
+	return segs, nil
+}
+
+// place sets value at segs[depth:] relative to node and returns the updated node.
+func place(node interface{}, segs []pathSeg, depth int, value interface{}, o FlattenOptions) (interface{}, error) {
+	_, empty := node.(hole)
+	if depth == len(segs) {
+		if empty {
+			return deepCopy(value), nil
+		}
+		// An empty container, as produced by Flatten with KeepEmpty, doesn't conflict with the same container type.
+		switch vt := value.(type) {
+		case []interface{}:
+			if _, ok := node.([]interface{}); ok && len(vt) == 0 {
+				return node, nil
+			}
+		case map[string]interface{}:
+			if _, ok := node.(map[string]interface{}); ok && len(vt) == 0 {
+				return node, nil
+			}
+		}
+		return nil, conflictErr(node, segs[:depth], o)
+	}
+
+	s := segs[depth]
+	if s.isIndex {
+		a, ok := node.([]interface{})
+		if !ok && !empty {
+			return nil, conflictErr(node, segs[:depth], o)
+		}
+		if s.index > o.MaxIndex {
+			return nil, fmt.Errorf("%w: %v is greater than %v", ErrIndexRange, formatPath(segs[:depth+1], o.Syntax, o.Separator), o.MaxIndex)
+		}
+		for len(a) <= s.index {
+			a = append(a, hole{})
+		}
+		el, err := place(a[s.index], segs, depth+1, value, o)
+		if err != nil {
+			return nil, err
+		}
+		a[s.index] = el
+		return a, nil
+	}
+	m, ok := node.(map[string]interface{})
+	if !ok {
+		if !empty {
+			return nil, conflictErr(node, segs[:depth], o)
+		}
+		m = make(map[string]interface{})
+	}
+	el, ok := m[s.key]
+	if !ok {
+		el = hole{}
+	}
+	el, err := place(el, segs, depth+1, value, o)
+	if err != nil {
+		return nil, err
+	}
+	m[s.key] = el
+	return m, nil
+}
+
+// checkValue returns an error if v, or anything nested in it, is not of a type Walk accepts.
+func checkValue(v interface{}) error {
+	switch vt := v.(type) {
+	case nil, bool, string, float64:
+		return nil
+	case []interface{}:
+		for _, el := range vt {
+			if err := checkValue(el); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[string]interface{}:
+		for _, el := range vt {
+			if err := checkValue(el); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("value %v of type %T is not a JSON type", v, v)
+}
+
+func conflictErr(node interface{}, segs []pathSeg, o FlattenOptions) error {
+	p := formatPath(segs, o.Syntax, o.Separator)
+	if len(segs) == 0 {
+		p = "root"
+	}
+	return fmt.Errorf("%w: %v is already set to %v", ErrConflict, p, t(fillHoles(node)))
+}
+
+// fillHoles replaces all the holes in v with nil.
+func fillHoles(v interface{}) interface{} {
+	switch vt := v.(type) {
+	case hole:
+		return nil
+	case []interface{}:
+		for i, el := range vt {
+			vt[i] = fillHoles(el)
+		}
+	case map[string]interface{}:
+		for k, el := range vt {
+			vt[k] = fillHoles(el)
+		}
+	}
+	return v
+}
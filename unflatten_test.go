@@ -0,0 +1,117 @@
+package jsonwalk_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zzwx/jsonwalk"
+)
+
+func ExampleUnflatten() {
+	v, err := jsonwalk.Unflatten([]jsonwalk.PathValue{
+		{Path: "Actors[0].name", Value: "Tom Cruise"},
+		{Path: "Actors[0].children[1]", Value: "Isabella Jane"},
+		{Path: "Actors[1].name", Value: "Robert Downey Jr."},
+	}, nil)
+	if err != nil {
+		return
+	}
+	b, _ := json.Marshal(v)
+	fmt.Println(string(b))
+	// Output:
+	// {"Actors":[{"children":[null,"Isabella Jane"],"name":"Tom Cruise"},{"name":"Robert Downey Jr."}]}
+}
+
+func TestUnflattenRoundTrip(t *testing.T) {
+	var f interface{}
+	err := json.Unmarshal([]byte(actorsJSON), &f)
+	if err != nil {
+		t.Errorf("error umarshalling json: %v", err)
+		return
+	}
+	var keys interface{}
+	err = json.Unmarshal([]byte(`{"a.b": {"c/d": [1, {"e~f": "g", "*": [], "[0]": {}}]}, "": {"": null, "x": [true]}}`), &keys)
+	if err != nil {
+		t.Errorf("error umarshalling json: %v", err)
+		return
+	}
+
+	for _, c := range []struct {
+		root *interface{}
+		opts *jsonwalk.FlattenOptions
+	}{
+		{&f, nil},
+		{&f, &jsonwalk.FlattenOptions{Syntax: jsonwalk.PointerPath}},
+		{&keys, &jsonwalk.FlattenOptions{Syntax: jsonwalk.EscapedPath, KeepEmpty: true}},
+		{&keys, &jsonwalk.FlattenOptions{Syntax: jsonwalk.EscapedPath, Separator: "/", KeepEmpty: true}},
+		{&keys, &jsonwalk.FlattenOptions{Syntax: jsonwalk.PointerPath, KeepEmpty: true}},
+	} {
+		got, err := jsonwalk.UnflattenMap(jsonwalk.FlattenMap(c.root, c.opts), c.opts)
+		if err != nil {
+			t.Errorf("options %+v: %v", c.opts, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, *c.root) {
+			t.Errorf("options %+v: expected %v, got %v", c.opts, *c.root, got)
+		}
+	}
+}
+
+func TestUnflattenErrors(t *testing.T) {
+	for _, pvs := range [][]jsonwalk.PathValue{
+		{{Path: "a", Value: 1.0}, {Path: "a.b", Value: 2.0}},
+		{{Path: "a.b", Value: 1.0}, {Path: "a[0]", Value: 2.0}},
+		{{Path: "a[1]", Value: nil}, {Path: "a[1]", Value: nil}},
+		{{Path: "", Value: "abc"}, {Path: "[0]", Value: "def"}},
+	} {
+		_, err := jsonwalk.Unflatten(pvs, nil)
+		var uerr *jsonwalk.UnflattenError
+		if !errors.As(err, &uerr) || !errors.Is(err, jsonwalk.ErrConflict) || uerr.Path != pvs[1].Path {
+			t.Errorf("%v: expected a conflict for %q, got %v", pvs, pvs[1].Path, err)
+		}
+	}
+
+	_, err := jsonwalk.Unflatten([]jsonwalk.PathValue{{Path: "a[x]", Value: 1.0}}, nil)
+	if err == nil || errors.Is(err, jsonwalk.ErrConflict) {
+		t.Errorf("expected a syntax error, got %v", err)
+	}
+	_, err = jsonwalk.Unflatten([]jsonwalk.PathValue{{Path: "a.*", Value: 1.0}}, &jsonwalk.FlattenOptions{Syntax: jsonwalk.EscapedPath})
+	if err == nil {
+		t.Errorf("expected an error for a wildcard")
+	}
+
+	var uerr *jsonwalk.UnflattenError
+	_, err = jsonwalk.Unflatten([]jsonwalk.PathValue{{Path: "a[50000000]", Value: 1.0}}, nil)
+	if !errors.As(err, &uerr) || !errors.Is(err, jsonwalk.ErrIndexRange) {
+		t.Errorf("expected an index range error, got %v", err)
+	}
+	pvs := []jsonwalk.PathValue{{Path: "a[0][3]", Value: 1.0}}
+	_, err = jsonwalk.Unflatten(pvs, &jsonwalk.FlattenOptions{MaxIndex: 2})
+	if !errors.Is(err, jsonwalk.ErrIndexRange) || !strings.Contains(err.Error(), "a[0][3] is greater than 2") {
+		t.Errorf("expected an index range error for a[0][3], got %v", err)
+	}
+	if _, err = jsonwalk.Unflatten(pvs, &jsonwalk.FlattenOptions{MaxIndex: 3}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err = jsonwalk.Unflatten([]jsonwalk.PathValue{{Path: "a[65535]", Value: 1.0}}, nil); err != nil {
+		t.Errorf("unexpected error for the default limit: %v", err)
+	}
+	if _, err = jsonwalk.Unflatten(pvs, &jsonwalk.FlattenOptions{MaxIndex: -1}); err != nil {
+		t.Errorf("unexpected error for a negative limit: %v", err)
+	}
+
+	// Values that aren't JSON types, such as ints parsed from flags, are errors rather than panics.
+	for _, pvs := range [][]jsonwalk.PathValue{
+		{{Path: "a", Value: 1}, {Path: "a.b", Value: 2.0}},
+		{{Path: "a", Value: 1.0}, {Path: "b", Value: []interface{}{int64(2)}}},
+	} {
+		_, err = jsonwalk.Unflatten(pvs, nil)
+		if !errors.As(err, &uerr) || !strings.Contains(err.Error(), "is not a JSON type") {
+			t.Errorf("%v: expected a type error, got %v", pvs, err)
+		}
+	}
+}
@@ -0,0 +1,445 @@
+package jsonwalk
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// ValidationError is a single violation of a JSON Schema found by Validate.
+type ValidationError struct {
+	// Path of the instance node that failed the validation.
+	Path WalkPath
+	// SchemaLocation is a JSON Pointer to the failed keyword in the schema, such as "/properties/age/minimum".
+	// $ref is followed, so the location points to where the keyword is actually written. For the keywords
+	// of the documents loaded by ValidateOptions.Resolve it's prefixed with their URI and "#".
+	SchemaLocation string
+	Message        string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("jsonwalk: |%v| fails %v: %v", e.Path.Path(), e.SchemaLocation, e.Message)
+}
+
+// ValidationErrors is returned by Validate when the instance doesn't conform to the schema.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	s := make([]string, len(e))
+	for i, err := range e {
+		s[i] = err.Error()
+	}
+	return strings.Join(s, "\n")
+}
+
+// ValidateOptions configures ValidateWith.
+type ValidateOptions struct {
+	// Resolve loads the schema document for the URI part of a $ref that doesn't point within the schema itself,
+	// such as "https://example.com/address.json" in "https://example.com/address.json#/$defs/street".
+	// Validation fails for such references if Resolve is nil, so nothing is ever fetched by default.
+	Resolve func(uri string) (interface{}, error)
+}
+
+// Validate checks the tree at root against a JSON Schema (draft 2020-12) at schema and returns ValidationErrors
+// listing all the violations, or nil if there are none:
+//
+//	err := jsonwalk.Validate(&f, &schema)
+//	var verrs jsonwalk.ValidationErrors
+//	if errors.As(err, &verrs) {
+//		for _, e := range verrs {
+//			fmt.Println(e.Path.Path(), e.SchemaLocation, e.Message)
+//		}
+//	}
+//
+// Supported keywords are type, enum, const, properties, patternProperties, additionalProperties, required,
+// prefixItems, items, minimum, maximum, exclusiveMinimum, exclusiveMaximum, minLength, maxLength,
+// minItems, maxItems, pattern, allOf, anyOf, oneOf, not and $ref. Other keywords, including formats, are ignored.
+// Patterns are Go regular expressions, which cover the commonly used subset of the ECMA-262 ones.
+//
+// $ref can point within the schema with a JSON Pointer fragment, such as "#/$defs/address".
+//
+// Errors that are not ValidationErrors are reported for the schemas that can't be applied,
+// such as for an invalid pattern or an unresolvable $ref.
+func Validate(root, schema *interface{}) error {
+	return ValidateWith(root, schema, nil)
+}
+
+// ValidateWith does the same as Validate, with the options.
+func ValidateWith(root, schema *interface{}, opts *ValidateOptions) error {
+	v := validator{
+		docs:     map[string]interface{}{"": *schema},
+		patterns: map[string]*regexp.Regexp{},
+		refs:     map[string]bool{},
+	}
+	if opts != nil {
+		v.opts = *opts
+	}
+	errs := v.validate(newWalkPath(), *root, *schema, schemaLoc{})
+	if v.err != nil {
+		return v.err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// schemaLoc is a location within one of the schema documents.
+type schemaLoc struct {
+	uri     string // "" for the validated schema
+	pointer string
+}
+
+func (l schemaLoc) child(tokens ...interface{}) schemaLoc {
+	for _, tok := range tokens {
+		l.pointer += "/" + escapePointerToken(fmt.Sprint(tok))
+	}
+	return l
+}
+
+func (l schemaLoc) String() string {
+	if l.uri == "" {
+		return l.pointer
+	}
+	return l.uri + "#" + l.pointer
+}
+
+type validator struct {
+	opts     ValidateOptions
+	docs     map[string]interface{} // schema documents by their URI
+	patterns map[string]*regexp.Regexp
+	refs     map[string]bool // $refs being followed for an instance path, to detect cycles
+	err      error           // the first error in the schema
+}
+
+func (v *validator) fail(err error) ValidationErrors {
+	if v.err == nil {
+		v.err = err
+	}
+	return nil
+}
+
+func (v *validator) valid(path walkPath, inst, schema interface{}, loc schemaLoc) bool {
+	return len(v.validate(path, inst, schema, loc)) == 0 && v.err == nil
+}
+
+func (v *validator) validate(path walkPath, inst, schema interface{}, loc schemaLoc) ValidationErrors {
+	if v.err != nil {
+		return nil
+	}
+	var errs ValidationErrors
+	errorf := func(keyword string, format string, a ...interface{}) {
+		errs = append(errs, &ValidationError{Path: path, SchemaLocation: loc.child(keyword).String(), Message: fmt.Sprintf(format, a...)})
+	}
+	switch s := schema.(type) {
+	case bool:
+		if !s {
+			errs = append(errs, &ValidationError{Path: path, SchemaLocation: loc.String(), Message: "no value is allowed"})
+		}
+		return errs
+	case map[string]interface{}:
+		keywords := maps.Keys(s)
+		slices.Sort(keywords)
+		for _, k := range keywords {
+			errs = append(errs, v.keyword(path, inst, s, k, loc, errorf)...)
+			if v.err != nil {
+				return nil
+			}
+		}
+		return errs
+	}
+	return v.fail(fmt.Errorf("jsonwalk: schema at %v is a %v rather than a Map or a Bool", loc, t(schema)))
+}
+
+// keyword applies a single keyword k of schema s, reporting the simple violations with errorf
+// and returning the ones found by the subschemas.
+func (v *validator) keyword(path walkPath, inst interface{}, s map[string]interface{}, k string, loc schemaLoc,
+	errorf func(keyword string, format string, a ...interface{})) ValidationErrors {
+	kv := s[k]
+	num := func() (float64, bool) {
+		f, ok := kv.(float64)
+		if !ok {
+			v.fail(fmt.Errorf("jsonwalk: %v at %v is not a number", k, loc))
+		}
+		return f, ok
+	}
+	switch k {
+	case "type":
+		var names []string
+		switch kt := kv.(type) {
+		case string:
+			names = []string{kt}
+		case []interface{}:
+			for _, n := range kt {
+				ns, ok := n.(string)
+				if !ok {
+					return v.fail(fmt.Errorf("jsonwalk: type at %v is not a string or an array of strings", loc))
+				}
+				names = append(names, ns)
+			}
+		default:
+			return v.fail(fmt.Errorf("jsonwalk: type at %v is not a string or an array of strings", loc))
+		}
+		if slices.IndexFunc(names, func(n string) bool { return isOfType(inst, n) }) < 0 {
+			errorf(k, "expected %v, got %v", strings.Join(names, " or "), typeName(t(inst)))
+		}
+	case "enum":
+		values, ok := kv.([]interface{})
+		if !ok {
+			return v.fail(fmt.Errorf("jsonwalk: enum at %v is not an array", loc))
+		}
+		if slices.IndexFunc(values, func(e interface{}) bool { return deepEqual(e, inst) }) < 0 {
+			errorf(k, "%v is not one of %v", compactJSON(inst), compactJSON(values))
+		}
+	case "const":
+		if !deepEqual(kv, inst) {
+			errorf(k, "expected %v, got %v", compactJSON(kv), compactJSON(inst))
+		}
+	case "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum":
+		f, isNum := inst.(float64)
+		limit, ok := num()
+		if !isNum || !ok {
+			return nil
+		}
+		switch {
+		case k == "minimum" && f < limit:
+			errorf(k, "%v is less than %v", compactJSON(f), compactJSON(limit))
+		case k == "maximum" && f > limit:
+			errorf(k, "%v is greater than %v", compactJSON(f), compactJSON(limit))
+		case k == "exclusiveMinimum" && f <= limit:
+			errorf(k, "%v is not greater than %v", compactJSON(f), compactJSON(limit))
+		case k == "exclusiveMaximum" && f >= limit:
+			errorf(k, "%v is not less than %v", compactJSON(f), compactJSON(limit))
+		}
+	case "minLength", "maxLength":
+		str, isStr := inst.(string)
+		limit, ok := num()
+		if !isStr || !ok {
+			return nil
+		}
+		n := utf8.RuneCountInString(str)
+		if k == "minLength" && float64(n) < limit || k == "maxLength" && float64(n) > limit {
+			errorf(k, "length %v is out of the limit of %v", n, limit)
+		}
+	case "minItems", "maxItems":
+		a, isArray := inst.([]interface{})
+		limit, ok := num()
+		if !isArray || !ok {
+			return nil
+		}
+		if k == "minItems" && float64(len(a)) < limit || k == "maxItems" && float64(len(a)) > limit {
+			errorf(k, "%v items are out of the limit of %v", len(a), limit)
+		}
+	case "pattern":
+		str, isStr := inst.(string)
+		if !isStr {
+			return nil
+		}
+		re := v.pattern(kv, loc.child(k))
+		if re != nil && !re.MatchString(str) {
+			errorf(k, "%v doesn't match %v", compactJSON(str), compactJSON(kv))
+		}
+	case "required":
+		m, isMap := inst.(map[string]interface{})
+		names, ok := kv.([]interface{})
+		if !ok {
+			return v.fail(fmt.Errorf("jsonwalk: required at %v is not an array", loc))
+		}
+		if !isMap {
+			return nil
+		}
+		for _, n := range names {
+			if _, ok := m[fmt.Sprint(n)]; !ok {
+				errorf(k, "missing member %q", n)
+			}
+		}
+	case "properties", "patternProperties", "additionalProperties":
+		m, isMap := inst.(map[string]interface{})
+		if !isMap {
+			return nil
+		}
+		return v.properties(path, m, s, k, loc)
+	case "prefixItems", "items":
+		a, isArray := inst.([]interface{})
+		if !isArray {
+			return nil
+		}
+		return v.items(path, a, s, k, loc)
+	case "allOf", "anyOf", "oneOf":
+		subs, ok := kv.([]interface{})
+		if !ok {
+			return v.fail(fmt.Errorf("jsonwalk: %v at %v is not an array", k, loc))
+		}
+		var all ValidationErrors
+		passed := 0
+		for i, sub := range subs {
+			subErrs := v.validate(path, inst, sub, loc.child(k, i))
+			if len(subErrs) == 0 {
+				passed++
+			}
+			all = append(all, subErrs...)
+		}
+		switch {
+		case k == "allOf":
+			return all
+		case k == "anyOf" && passed == 0:
+			errorf(k, "none of the %v schemas match", len(subs))
+		case k == "oneOf" && passed != 1:
+			errorf(k, "%v of the %v schemas match instead of exactly one", passed, len(subs))
+		}
+	case "not":
+		if v.valid(path, inst, kv, loc.child(k)) {
+			errorf(k, "the value is not allowed")
+		}
+	case "$ref":
+		ref, ok := kv.(string)
+		if !ok {
+			return v.fail(fmt.Errorf("jsonwalk: $ref at %v is not a string", loc))
+		}
+		target, targetLoc, err := v.resolve(ref, loc)
+		if err != nil {
+			return v.fail(err)
+		}
+		key := targetLoc.String() + "|" + path.Path()
+		if v.refs[key] {
+			return v.fail(fmt.Errorf("jsonwalk: $ref at %v makes a cycle", loc))
+		}
+		v.refs[key] = true
+		defer delete(v.refs, key)
+		return v.validate(path, inst, target, targetLoc)
+	}
+	return nil
+}
+
+func (v *validator) properties(path walkPath, m map[string]interface{}, s map[string]interface{}, k string, loc schemaLoc) ValidationErrors {
+	var errs ValidationErrors
+	props, _ := s["properties"].(map[string]interface{})
+	patternProps, _ := s["patternProperties"].(map[string]interface{})
+	keys := maps.Keys(m)
+	slices.Sort(keys)
+	for _, key := range keys {
+		switch k {
+		case "properties":
+			if sub, ok := props[key]; ok {
+				errs = append(errs, v.validate(path.MapEl(key), m[key], sub, loc.child(k, key))...)
+			}
+		case "patternProperties":
+			patterns := maps.Keys(patternProps)
+			slices.Sort(patterns)
+			for _, p := range patterns {
+				if re := v.pattern(p, loc.child(k)); re != nil && re.MatchString(key) {
+					errs = append(errs, v.validate(path.MapEl(key), m[key], patternProps[p], loc.child(k, p))...)
+				}
+			}
+		case "additionalProperties":
+			if _, ok := props[key]; ok {
+				continue
+			}
+			if slices.IndexFunc(maps.Keys(patternProps), func(p string) bool {
+				re := v.pattern(p, loc.child("patternProperties"))
+				return re != nil && re.MatchString(key)
+			}) >= 0 {
+				continue
+			}
+			errs = append(errs, v.validate(path.MapEl(key), m[key], s[k], loc.child(k))...)
+		}
+	}
+	return errs
+}
+
+func (v *validator) items(path walkPath, a []interface{}, s map[string]interface{}, k string, loc schemaLoc) ValidationErrors {
+	var errs ValidationErrors
+	prefix, _ := s["prefixItems"].([]interface{})
+	for i, el := range a {
+		switch {
+		case k == "prefixItems" && i < len(prefix):
+			errs = append(errs, v.validate(path.ArrayEl(i), el, prefix[i], loc.child(k, i))...)
+		case k == "items" && i >= len(prefix):
+			errs = append(errs, v.validate(path.ArrayEl(i), el, s[k], loc.child(k))...)
+		}
+	}
+	return errs
+}
+
+func (v *validator) pattern(p interface{}, loc schemaLoc) *regexp.Regexp {
+	ps, ok := p.(string)
+	if !ok {
+		v.fail(fmt.Errorf("jsonwalk: pattern at %v is not a string", loc))
+		return nil
+	}
+	if re, ok := v.patterns[ps]; ok {
+		return re
+	}
+	re, err := regexp.Compile(ps)
+	if err != nil {
+		v.fail(fmt.Errorf("jsonwalk: pattern at %v: %w", loc, err))
+		return nil
+	}
+	v.patterns[ps] = re
+	return re
+}
+
+// resolve returns the schema ref points to from the schema at loc, along with its location.
+func (v *validator) resolve(ref string, loc schemaLoc) (interface{}, schemaLoc, error) {
+	uri, fragment, _ := strings.Cut(ref, "#")
+	if uri == "" {
+		uri = loc.uri
+	}
+	doc, ok := v.docs[uri]
+	if !ok {
+		if v.opts.Resolve == nil {
+			return nil, schemaLoc{}, fmt.Errorf("jsonwalk: $ref %q at %v is not within the schema", ref, loc)
+		}
+		var err error
+		doc, err = v.opts.Resolve(uri)
+		if err != nil {
+			return nil, schemaLoc{}, fmt.Errorf("jsonwalk: $ref %q at %v: %w", ref, loc, err)
+		}
+		v.docs[uri] = doc
+	}
+	fragment, err := url.PathUnescape(fragment)
+	if err != nil {
+		return nil, schemaLoc{}, fmt.Errorf("jsonwalk: $ref %q at %v: %w", ref, loc, err)
+	}
+	tokens, err := parsePointer(fragment)
+	if err != nil {
+		return nil, schemaLoc{}, fmt.Errorf("jsonwalk: $ref %q at %v: %w", ref, loc, err)
+	}
+	target, err := pointerGet(doc, tokens)
+	if err != nil {
+		return nil, schemaLoc{}, fmt.Errorf("jsonwalk: $ref %q at %v: %w", ref, loc, err)
+	}
+	return target, schemaLoc{uri: uri, pointer: fragment}, nil
+}
+
+// isOfType reports whether v is of the JSON Schema type name.
+func isOfType(v interface{}, name string) bool {
+	if name == "integer" {
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f) && !math.IsInf(f, 0)
+	}
+	return typeName(t(v)) == name
+}
+
+// typeName returns the JSON Schema type name for nt, using "number" for all the numbers.
+func typeName(nt NodeValueType) string {
+	switch nt {
+	case Nil:
+		return "null"
+	case Bool:
+		return "boolean"
+	case String:
+		return "string"
+	case Float64:
+		return "number"
+	case Array:
+		return "array"
+	}
+	return "object"
+}
@@ -0,0 +1,128 @@
+package jsonwalk_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/zzwx/jsonwalk"
+)
+
+func ExampleValidate() {
+	var f, schema interface{}
+	err := json.Unmarshal([]byte(actorsJSON), &f)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal([]byte(`{
+		"type": "object",
+		"required": ["Actors"],
+		"properties": {
+			"Actors": {"type": "array", "items": {"$ref": "#/$defs/actor"}}
+		},
+		"$defs": {
+			"actor": {
+				"type": "object",
+				"required": ["name", "age"],
+				"properties": {
+					"age": {"type": "integer", "maximum": 55},
+					"wife": {"type": "string"},
+					"children": {"type": "array", "maxItems": 3, "items": {"pattern": "^[A-Z][a-z]+$"}}
+				}
+			}
+		}
+	}`), &schema)
+	if err != nil {
+		return
+	}
+	err = jsonwalk.Validate(&f, &schema)
+	var verrs jsonwalk.ValidationErrors
+	if errors.As(err, &verrs) {
+		for _, e := range verrs {
+			fmt.Printf("%v %v: %v\n", e.Path.Path(), e.SchemaLocation, e.Message)
+		}
+	}
+	// Output:
+	// Actors[0].age /$defs/actor/properties/age/maximum: 56 is greater than 55
+	// Actors[0].children[1] /$defs/actor/properties/children/items/pattern: "Isabella Jane" doesn't match "^[A-Z][a-z]+$"
+	// Actors[0].wife /$defs/actor/properties/wife/type: expected string, got null
+	// Actors[1].children[0] /$defs/actor/properties/children/items/pattern: "Indio Falconer" doesn't match "^[A-Z][a-z]+$"
+	// Actors[1].children[1] /$defs/actor/properties/children/items/pattern: "Avri Roel" doesn't match "^[A-Z][a-z]+$"
+	// Actors[1].children[2] /$defs/actor/properties/children/items/pattern: "Exton Elias" doesn't match "^[A-Z][a-z]+$"
+}
+
+func TestValidate(t *testing.T) {
+	for _, test := range []struct {
+		schema, instance string
+		expected         string // locations of the violations
+	}{
+		{`{"type": ["string", "null"]}`, `null`, "[]"},
+		{`{"type": "integer"}`, `1.5`, "[/type]"},
+		{`{"enum": [1, "a", {"b": [true]}]}`, `{"b": [true]}`, "[]"},
+		{`{"const": [1, 2]}`, `[1, 3]`, "[/const]"},
+		{`{"exclusiveMinimum": 1, "maximum": 3}`, `1`, "[/exclusiveMinimum]"},
+		{`{"minLength": 2, "maxLength": 2}`, `"ăâ"`, "[]"},
+		{`{"minItems": 1}`, `[]`, "[/minItems]"},
+		{`{"properties": {"a": true}, "patternProperties": {"^x-": {"type": "string"}}, "additionalProperties": false}`,
+			`{"a": 1, "x-b": 2, "c": 3}`, "[/additionalProperties /patternProperties/^x-/type]"},
+		{`{"prefixItems": [{"type": "string"}], "items": {"type": "number"}}`, `["a", 1, "b"]`, "[/items/type]"},
+		{`{"anyOf": [{"type": "string"}, {"type": "number"}]}`, `true`, "[/anyOf]"},
+		{`{"oneOf": [{"type": "number"}, {"type": "integer"}]}`, `1`, "[/oneOf]"},
+		{`{"allOf": [{"minimum": 2}, {"maximum": 0}]}`, `1`, "[/allOf/0/minimum /allOf/1/maximum]"},
+		{`{"not": {"type": "null"}}`, `null`, "[/not]"},
+		{`{"$defs": {"list": {"type": "array", "items": {"$ref": "#/$defs/list"}}}, "$ref": "#/$defs/list"}`, `[[[]], [1]]`, "[/$defs/list/type]"},
+	} {
+		var schema, instance interface{}
+		if err := json.Unmarshal([]byte(test.schema), &schema); err != nil {
+			t.Errorf("error umarshalling json: %v", err)
+			continue
+		}
+		if err := json.Unmarshal([]byte(test.instance), &instance); err != nil {
+			t.Errorf("error umarshalling json: %v", err)
+			continue
+		}
+		err := jsonwalk.Validate(&instance, &schema)
+		var verrs jsonwalk.ValidationErrors
+		if err != nil && !errors.As(err, &verrs) {
+			t.Errorf("%v: unexpected error %v", test.schema, err)
+			continue
+		}
+		var got []string
+		for _, e := range verrs {
+			got = append(got, e.SchemaLocation)
+		}
+		if fmt.Sprint(got) != test.expected {
+			t.Errorf("%v with %v: expected violations %v, got %v", test.schema, test.instance, test.expected, got)
+		}
+	}
+}
+
+func TestValidateRefs(t *testing.T) {
+	var f, schema interface{}
+	_ = json.Unmarshal([]byte(`{"street": 5}`), &f)
+	_ = json.Unmarshal([]byte(`{"$ref": "https://example.com/address.json#/$defs/address"}`), &schema)
+	if err := jsonwalk.Validate(&f, &schema); err == nil || errors.As(err, new(jsonwalk.ValidationErrors)) {
+		t.Errorf("expected a remote $ref to fail without Resolve, got %v", err)
+	}
+	var address interface{}
+	_ = json.Unmarshal([]byte(`{"$defs": {"address": {"properties": {"street": {"type": "string"}}}}}`), &address)
+	err := jsonwalk.ValidateWith(&f, &schema, &jsonwalk.ValidateOptions{
+		Resolve: func(uri string) (interface{}, error) {
+			if uri != "https://example.com/address.json" {
+				return nil, fmt.Errorf("unexpected uri %v", uri)
+			}
+			return address, nil
+		},
+	})
+	var verrs jsonwalk.ValidationErrors
+	if !errors.As(err, &verrs) || len(verrs) != 1 || verrs[0].Path.Path() != "street" ||
+		verrs[0].SchemaLocation != "https://example.com/address.json#/$defs/address/properties/street/type" {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	_ = json.Unmarshal([]byte(`{"$defs": {"a": {"$ref": "#/$defs/b"}, "b": {"$ref": "#/$defs/a"}}, "$ref": "#/$defs/a"}`), &schema)
+	if err := jsonwalk.Validate(&f, &schema); err == nil || errors.As(err, new(jsonwalk.ValidationErrors)) {
+		t.Errorf("expected a $ref cycle error, got %v", err)
+	}
+}
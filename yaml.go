@@ -0,0 +1,354 @@
+package jsonwalk
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxYAMLNodes limits the number of nodes a YAML document may expand to through its aliases,
+// which guards against alias bombs ("billion laughs").
+const maxYAMLNodes = 1 << 20
+
+// YAMLPosition is the place of a node in the YAML source. Line and Column start at 1.
+type YAMLPosition struct {
+	Line   int
+	Column int
+}
+
+// YAMLDocument is a single document of a YAML stream decoded by DecodeYAML.
+type YAMLDocument struct {
+	// Root is the decoded document, made of the same types json.Unmarshal produces, ready to be walked.
+	Root      interface{}
+	positions map[string]YAMLPosition
+}
+
+// Position returns the place in the YAML source of the node at path p of d.Root.
+// Nodes that come from an alias are reported at the place of the anchored node they copy.
+func (d *YAMLDocument) Position(p WalkPath) (YAMLPosition, bool) {
+	pos, ok := d.positions[formatPath(pathSegments(p), EscapedPath, ".")]
+	return pos, ok
+}
+
+// DecodeYAML decodes all the documents of the YAML stream read from r into the types json.Unmarshal produces,
+// so that they can be walked as any other document:
+//
+//   - integers become float64, timestamps become RFC 3339 strings, .inf and .nan stay strings;
+//   - mapping keys that aren't strings are formatted as strings: 1 as "1", true as "true",
+//     and collections as compact JSON;
+//   - merge keys ("<<") are merged into the mapping they appear in;
+//   - aliases are replaced with copies of the anchored nodes. An alias within its own anchor is an error,
+//     as it would make the document infinite.
+func DecodeYAML(r io.Reader) ([]*YAMLDocument, error) {
+	var docs []*YAMLDocument
+	dec := yaml.NewDecoder(r)
+	for {
+		var n yaml.Node
+		if err := dec.Decode(&n); err != nil {
+			if errors.Is(err, io.EOF) {
+				return docs, nil
+			}
+			return nil, fmt.Errorf("jsonwalk: yaml: %w", err)
+		}
+		d := &YAMLDocument{positions: map[string]YAMLPosition{}}
+		yc := &yamlConverter{doc: d, open: map[*yaml.Node]bool{}}
+		root := &n
+		if n.Kind == yaml.DocumentNode && len(n.Content) > 0 {
+			root = n.Content[0]
+		}
+		v, err := yc.convert(root, nil)
+		if err != nil {
+			return nil, err
+		}
+		d.Root = v
+		docs = append(docs, d)
+	}
+}
+
+// WalkYAML decodes the YAML stream read from r with DecodeYAML and walks its documents one after another
+// with Walk. Use DecodeYAML directly to walk the documents in another way or to find the source lines of the nodes.
+func WalkYAML(r io.Reader, callback WalkCallback) error {
+	docs, err := DecodeYAML(r)
+	if err != nil {
+		return err
+	}
+	for _, d := range docs {
+		Walk(&d.Root, callback)
+	}
+	return nil
+}
+
+// yamlConverter turns yaml.Node trees into the walkable types.
+type yamlConverter struct {
+	doc   *YAMLDocument
+	open  map[*yaml.Node]bool // nodes being converted, to detect aliases within their own anchors
+	nodes int
+}
+
+func (yc *yamlConverter) convert(n *yaml.Node, segs []pathSeg) (interface{}, error) {
+	yc.nodes++
+	if yc.nodes > maxYAMLNodes {
+		return nil, fmt.Errorf("jsonwalk: yaml: document expands to more than %d nodes", maxYAMLNodes)
+	}
+	if n.Kind != yaml.AliasNode {
+		yc.doc.positions[formatPath(segs, EscapedPath, ".")] = YAMLPosition{Line: n.Line, Column: n.Column}
+	}
+	switch n.Kind {
+	case yaml.AliasNode:
+		if yc.open[n.Alias] {
+			return nil, fmt.Errorf("jsonwalk: yaml: line %d: alias *%v is within its own anchor", n.Line, n.Value)
+		}
+		yc.nodes--
+		return yc.convert(n.Alias, segs)
+	case yaml.ScalarNode:
+		return yc.scalar(n)
+	case yaml.SequenceNode:
+		yc.open[n] = true
+		defer delete(yc.open, n)
+		a := make([]interface{}, len(n.Content))
+		for i, el := range n.Content {
+			v, err := yc.convert(el, append(segs[:len(segs):len(segs)], pathSeg{index: i, isIndex: true}))
+			if err != nil {
+				return nil, err
+			}
+			a[i] = v
+		}
+		return a, nil
+	case yaml.MappingNode:
+		yc.open[n] = true
+		defer delete(yc.open, n)
+		m := map[string]interface{}{}
+		if err := yc.mapping(n, segs, m, false); err != nil {
+			return nil, err
+		}
+		return m, nil
+	}
+	return nil, nil // an empty document
+}
+
+// mapping adds the members of the mapping n to m. Merged members don't replace the ones already in m.
+func (yc *yamlConverter) mapping(n *yaml.Node, segs []pathSeg, m map[string]interface{}, merged bool) error {
+	var merges []*yaml.Node
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], n.Content[i+1]
+		if k.Kind == yaml.ScalarNode && k.Tag == "!!merge" {
+			merges = append(merges, v)
+			continue
+		}
+		key, err := yc.key(k)
+		if err != nil {
+			return err
+		}
+		if _, ok := m[key]; ok && merged {
+			continue
+		}
+		el, err := yc.convert(v, append(segs[:len(segs):len(segs)], pathSeg{key: key}))
+		if err != nil {
+			return err
+		}
+		m[key] = el
+	}
+	// Explicit members go first, so that they override the merged ones.
+	for _, mn := range merges {
+		targets := []*yaml.Node{mn}
+		if mn.Kind == yaml.SequenceNode {
+			targets = mn.Content
+		}
+		for _, t := range targets {
+			if t.Kind == yaml.AliasNode {
+				if yc.open[t.Alias] {
+					return fmt.Errorf("jsonwalk: yaml: line %d: alias *%v is within its own anchor", t.Line, t.Value)
+				}
+				t = t.Alias
+			}
+			if t.Kind != yaml.MappingNode {
+				return fmt.Errorf("jsonwalk: yaml: line %d: can only merge mappings", t.Line)
+			}
+			yc.open[t] = true
+			err := yc.mapping(t, segs, m, true)
+			delete(yc.open, t)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// key formats a mapping key as a string.
+func (yc *yamlConverter) key(k *yaml.Node) (string, error) {
+	for k.Kind == yaml.AliasNode {
+		k = k.Alias
+	}
+	if k.Kind == yaml.ScalarNode && k.Tag == "!!str" {
+		return k.Value, nil
+	}
+	saved := yc.doc.positions
+	yc.doc.positions = map[string]YAMLPosition{} // positions within keys aren't reachable by paths
+	v, err := yc.convert(k, nil)
+	yc.doc.positions = saved
+	if err != nil {
+		return "", err
+	}
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	return compactJSON(v), nil
+}
+
+// scalar resolves a scalar node into nil, bool, string or float64.
+func (yc *yamlConverter) scalar(n *yaml.Node) (interface{}, error) {
+	var v interface{}
+	if err := n.Decode(&v); err != nil {
+		return nil, fmt.Errorf("jsonwalk: yaml: line %d: %w", n.Line, err)
+	}
+	switch vt := v.(type) {
+	case int:
+		return float64(vt), nil
+	case int64:
+		return float64(vt), nil
+	case uint64:
+		return float64(vt), nil
+	case float64:
+		if math.IsInf(vt, 0) || math.IsNaN(vt) {
+			return n.Value, nil // not representable in JSON
+		}
+		return vt, nil
+	case time.Time:
+		return vt.Format(time.RFC3339Nano), nil
+	case nil, bool, string:
+		return vt, nil
+	}
+	return n.Value, nil
+}
+
+// yamlWriter implements WalkLeaveCallback by writing the walked document as block-style YAML.
+type yamlWriter struct {
+	w    io.Writer
+	open []yamlFrame
+	docs int
+	err  error
+}
+
+// yamlFrame is an open container which children are being written.
+type yamlFrame struct {
+	nodeValueType NodeValueType
+	indent        string // written before the children
+	inline        bool   // an array element, which first child goes on the line of its "-"
+	children      int
+}
+
+// NewYAMLWriter returns a WalkLeaveCallback that writes the walked document to w as block-style YAML:
+//
+//	Actors:
+//	  - Born At: New York City, NY
+//	    age: 56
+//	    children:
+//	      - Suri
+//
+// Strings are quoted only when they would otherwise be read as another type or break the syntax.
+// Walking several documents with the same writer separates them with "---".
+// Containers are opened in C and closed in L, so the callback has to be passed to Walk directly
+// or through the callbacks that forward L, such as Multi, Filter or Limit.
+func NewYAMLWriter(w io.Writer) *yamlWriter {
+	return &yamlWriter{w: w}
+}
+
+// Err returns the first error that occurred while writing. Nothing is written after an error.
+func (yw *yamlWriter) Err() error {
+	return yw.err
+}
+
+func (yw *yamlWriter) C(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType) {
+	if yw.err != nil || len(yw.open) != path.Level() {
+		return // not under the innermost open container, such as under a container Limit didn't pass
+	}
+	var b strings.Builder
+	if len(yw.open) == 0 {
+		if yw.docs > 0 {
+			b.WriteString("---\n")
+		}
+		yw.docs++
+	} else {
+		parent := &yw.open[len(yw.open)-1]
+		switch {
+		case parent.children > 0 || len(yw.open) == 1 && !parent.inline:
+			b.WriteString(parent.indent)
+		case parent.inline:
+			b.WriteByte(' ')
+		default:
+			b.WriteString("\n" + parent.indent)
+		}
+		parent.children++
+		if parent.nodeValueType == Map {
+			b.WriteString(yamlScalar(key.(string)) + ":")
+		} else {
+			b.WriteByte('-')
+		}
+	}
+	switch nodeValueType {
+	case Array, Map:
+		f := yamlFrame{nodeValueType: nodeValueType}
+		if n := len(yw.open); n > 0 {
+			f.indent = yw.open[n-1].indent + "  "
+			f.inline = yw.open[n-1].nodeValueType == Array
+		}
+		yw.open = append(yw.open, f)
+	default:
+		if len(yw.open) > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(yamlScalar(value) + "\n")
+	}
+	yw.write(b.String())
+}
+
+func (yw *yamlWriter) L(path WalkPath, key interface{}, value interface{}, nodeValueType NodeValueType) {
+	if yw.err != nil || len(yw.open) != path.Level()+1 {
+		return
+	}
+	f := yw.open[len(yw.open)-1]
+	yw.open = yw.open[:len(yw.open)-1]
+	if f.children > 0 {
+		return
+	}
+	s := "[]\n"
+	if f.nodeValueType == Map {
+		s = "{}\n"
+	}
+	if len(yw.open) > 0 {
+		s = " " + s
+	}
+	yw.write(s)
+}
+
+func (yw *yamlWriter) write(s string) {
+	if _, err := io.WriteString(yw.w, s); err != nil {
+		yw.err = err
+	}
+}
+
+// yamlScalar formats a leaf value or a key as a YAML scalar that reads back as the same value.
+func yamlScalar(v interface{}) string {
+	s, ok := v.(string)
+	if !ok {
+		return compactJSON(v)
+	}
+	b, err := yaml.Marshal(s)
+	if out := strings.TrimSuffix(string(b), "\n"); err == nil && !strings.Contains(out, "\n") {
+		return out
+	}
+	return compactJSON(s) // a JSON string is a valid double-quoted YAML scalar
+}
+
+// WriteYAML writes the tree at root to w with NewYAMLWriter, walking it with WalkSorted.
+func WriteYAML(w io.Writer, root *interface{}) error {
+	yw := NewYAMLWriter(w)
+	WalkSorted(root, yw)
+	return yw.Err()
+}
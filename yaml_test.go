@@ -0,0 +1,106 @@
+package jsonwalk_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/zzwx/jsonwalk"
+)
+
+func ExampleWalkYAML() {
+	src := `
+defaults: &defaults
+  retries: 3
+  since: 2001-12-14
+service:
+  <<: *defaults
+  name: "api"
+  ports: [80, 443]
+  1: one
+`
+	err := jsonwalk.WalkYAML(strings.NewReader(src), jsonwalk.Filter(
+		func(path jsonwalk.WalkPath, key interface{}, value interface{}, nodeValueType jsonwalk.NodeValueType) bool {
+			return strings.HasPrefix(path.Path(), "service.") && nodeValueType != jsonwalk.Array
+		},
+		jsonwalk.Callback(func(path jsonwalk.WalkPath, key interface{}, value interface{}, nodeValueType jsonwalk.NodeValueType) {
+			fmt.Printf("%v = %#v\n", path.Path(), value)
+		}),
+	))
+	if err != nil {
+		fmt.Println(err)
+	}
+	// Unordered output:
+	// service.retries = 3
+	// service.since = "2001-12-14T00:00:00Z"
+	// service.name = "api"
+	// service.1 = "one"
+	// service.ports[0] = 80
+	// service.ports[1] = 443
+}
+
+func ExampleWriteYAML() {
+	var f interface{}
+	err := json.Unmarshal([]byte(`{"Actors": [{"name": "Tom", "children": ["Suri", "Isabella"], "wife": null},
+		{"name": "yes", "children": [], "tags": [[1, 2], {}], "bio": "a: b\nc"}]}`), &f)
+	if err != nil {
+		return
+	}
+	_ = jsonwalk.WriteYAML(os.Stdout, &f)
+	// Output:
+	// Actors:
+	//   - children:
+	//       - Suri
+	//       - Isabella
+	//     name: Tom
+	//     wife: null
+	//   - bio: "a: b\nc"
+	//     children: []
+	//     name: "yes"
+	//     tags:
+	//       - - 1
+	//         - 2
+	//       - {}
+}
+
+func TestDecodeYAML(t *testing.T) {
+	docs, err := jsonwalk.DecodeYAML(strings.NewReader("a: &x\n  b: [1, 2]\nc: *x\n---\n- true\n"))
+	if err != nil {
+		t.Errorf("error decoding yaml: %v", err)
+		return
+	}
+	if len(docs) != 2 {
+		t.Errorf("expected 2 documents, got %v", len(docs))
+		return
+	}
+	var b bytes.Buffer
+	yw := jsonwalk.NewYAMLWriter(&b)
+	for _, d := range docs {
+		jsonwalk.WalkSorted(&d.Root, yw)
+	}
+	expected := "a:\n  b:\n    - 1\n    - 2\nc:\n  b:\n    - 1\n    - 2\n---\n- true\n"
+	if b.String() != expected || yw.Err() != nil {
+		t.Errorf("expected:\n%v\ngot:\n%v", expected, b.String())
+	}
+
+	positions := map[string]jsonwalk.YAMLPosition{}
+	jsonwalk.Walk(&docs[0].Root, jsonwalk.Callback(func(path jsonwalk.WalkPath, key interface{}, value interface{}, nodeValueType jsonwalk.NodeValueType) {
+		if pos, ok := docs[0].Position(path); ok {
+			positions[path.Path()] = pos
+		}
+	}))
+	for p, pos := range map[string]jsonwalk.YAMLPosition{"": {1, 1}, "a.b[1]": {2, 10}, "c.b[0]": {2, 7}} {
+		if positions[p] != pos {
+			t.Errorf("expected %v at %v, got %v", p, pos, positions[p])
+		}
+	}
+
+	for _, src := range []string{"a: &x\n  b: *x\n", "&x [1, *x]\n", "a: &x\n  <<: *x\n"} {
+		if err := jsonwalk.WalkYAML(strings.NewReader(src), nil); err == nil {
+			t.Errorf("expected an error for a recursive alias in %q", src)
+		}
+	}
+}